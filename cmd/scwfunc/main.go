@@ -0,0 +1,62 @@
+// Command scwfunc helps to work with Scaleway Functions locally.
+//
+// Usage:
+//
+//	scwfunc invoke --event event.json [--package ./cmd]
+//
+// The invoke command runs the main package of the function, which must call local.RunCLI, to invoke the handler
+// once with an event document, a core.APIGatewayProxyRequest or a core.CoreRuntimeRequest, without starting a web
+// server. The resulting status, headers and body are printed as local.CoreProcessing produces them. It is the same
+// as running:
+//
+//	go run ./cmd invoke --event event.json
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+const defaultPackage = "./cmd"
+
+var errUsage = errors.New("usage: scwfunc invoke --event event.json [--package " + defaultPackage + "]")
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command given by args, the output of the function is written to stdout and stderr.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] != "invoke" {
+		return errUsage
+	}
+
+	flags := flag.NewFlagSet("invoke", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	eventFile := flags.String("event", "", "path of the event JSON document")
+	pkg := flags.String("package", defaultPackage, "main package of the function, calling local.RunCLI")
+
+	if err := flags.Parse(args[1:]); err != nil || *eventFile == "" {
+		return errUsage
+	}
+
+	if _, err := os.Stat(*eventFile); err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, "go", "run", *pkg, "invoke", "--event", *eventFile)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunInvoke(t *testing.T) {
+	t.Parallel()
+
+	eventFile := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventFile,
+		[]byte(`{"path": "/orders", "httpMethod": "POST", "queryStringParameters": {"id": "1"}, "body": "{}"}`), 0o600))
+
+	out := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	require.NoError(t, run(context.Background(),
+		[]string{"invoke", "--event", eventFile, "--package", "./testdata/function"}, out, stderr), stderr.String())
+
+	assert.Contains(t, out.String(), "HTTP/1.1 201 Created\n")
+	assert.Contains(t, out.String(), "\nX-Path: /orders?id=1\n")
+	assert.Contains(t, out.String(), "\nServer: envoy\n")
+	assert.Contains(t, out.String(), "\n\ncreated")
+}
+
+func TestRunUsage(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{nil, {"unknown"}, {"invoke"}, {"invoke", "--unknown"}} {
+		assert.ErrorIs(t, run(context.Background(), args, &bytes.Buffer{}, &bytes.Buffer{}), errUsage)
+	}
+
+	err := run(context.Background(), []string{"invoke", "--event", filepath.Join(t.TempDir(), "missing.json")},
+		&bytes.Buffer{}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/scaleway/serverless-functions-go/local"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Path", r.URL.Path+"?"+r.URL.RawQuery)
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte("created"))
+}
+
+func main() {
+	local.RunCLI(handle)
+}
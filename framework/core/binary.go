@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidBase64Body is returned when the body of a response flagged as base64 encoded can not be decoded.
+var ErrInvalidBase64Body = errors.New("response body is not a base64 encoded string")
+
+// BinaryBody encodes data as the body of a response with IsBase64Encoded set: a JSON string of the base64 data.
+func BinaryBody(data []byte) json.RawMessage {
+	// Marshalling a string can not fail.
+	body, _ := json.Marshal(base64.StdEncoding.EncodeToString(data))
+
+	return body
+}
+
+// BinaryResponse returns the response of a ScwFuncV2 handler sending binary data, like images or PDF documents.
+func BinaryResponse(statusCode int, contentType string, data []byte) ResponseHTTP {
+	return ResponseHTTP{
+		StatusCode:      statusCode,
+		Body:            BinaryBody(data),
+		Headers:         map[string][]string{contentTypeHeaderKey: {contentType}},
+		IsBase64Encoded: true,
+	}
+}
+
+// WriteBinary writes binary data, like images or PDF documents, as the response of a http.HandlerFunc handler.
+// The data is base64 encoded in the response of the function and decoded by the platform, so any content is sent
+// unaltered to the caller. The status code is 200, set other headers before calling WriteBinary.
+func WriteBinary(w http.ResponseWriter, contentType string, data []byte) error {
+	return WriteResponse(w, BinaryResponse(http.StatusOK, contentType, data))
+}
+
+// DecodedBody returns the body sent to the caller: base64 encoded bodies are decoded, JSON strings are unquoted
+// and other bodies are returned as is. Bodies of responses returned by GetResponse without envelope are always
+// returned as is.
+func (r *ResponseHTTP) DecodedBody() ([]byte, error) {
+	if r.raw {
+		return r.Body, nil
+	}
+
+	if !r.IsBase64Encoded || len(r.Body) == 0 {
+		var bodyString string
+		if err := json.Unmarshal(r.Body, &bodyString); err == nil {
+			return []byte(bodyString), nil
+		}
+
+		return r.Body, nil
+	}
+
+	var bodyString string
+	if err := json.Unmarshal(r.Body, &bodyString); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBase64Body, err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(bodyString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBase64Body, err)
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBinary(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`"valid JSON string"`)
+
+	recorder := httptest.NewRecorder()
+	require.NoError(t, WriteBinary(recorder, "application/pdf", data))
+
+	//nolint:bodyclose
+	resp, err := GetResponse(recorder.Result())
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, resp.IsBase64Encoded)
+	assert.Equal(t, []string{"application/pdf"}, resp.Headers["Content-Type"])
+
+	decoded, err := resp.DecodedBody()
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+}
+
+func TestGetResponseNotUTF8(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "image/png")
+	_, _ = recorder.Write(data)
+
+	//nolint:bodyclose
+	resp, err := GetResponse(recorder.Result())
+	require.NoError(t, err)
+
+	assert.True(t, resp.IsBase64Encoded)
+	assert.True(t, IsJSON(resp.Body))
+
+	decoded, err := resp.DecodedBody()
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+}
+
+func TestResponseDecodedBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response ResponseHTTP
+		expected string
+		err      bool
+	}{
+		{name: "json string", response: ResponseHTTP{Body: json.RawMessage(`"text"`)}, expected: "text"},
+		{name: "json object", response: ResponseHTTP{Body: json.RawMessage(`{"key":"value"}`)}, expected: `{"key":"value"}`},
+		{name: "binary", response: BinaryResponse(http.StatusOK, "image/png", []byte("png")), expected: "png"},
+		{name: "empty", response: ResponseHTTP{IsBase64Encoded: true}, expected: ""},
+		{name: "not a string", response: ResponseHTTP{Body: json.RawMessage(`42`), IsBase64Encoded: true}, err: true},
+		{name: "not base64", response: ResponseHTTP{Body: json.RawMessage(`"!!"`), IsBase64Encoded: true}, err: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, err := tt.response.DecodedBody()
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidBase64Body)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+}
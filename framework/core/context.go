@@ -1,18 +1,112 @@
 package core
 
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// requestIDHeaderKey is the header used by the infrastructure to identify a request.
+const requestIDHeaderKey = "X-Request-Id"
+
 // ExecutionContext type for the context of execution of the function including memory, function name and version...
 type ExecutionContext struct {
 	MemoryLimitInMB int    `json:"memoryLimitInMb"`
 	FunctionName    string `json:"functionName"`
 	FunctionVersion string `json:"functionVersion"`
+	NamespaceName   string `json:"namespaceName,omitempty"`
+	Region          string `json:"region,omitempty"`
 }
 
-// GetExecutionContext is used to create a new execution context and make it available, for offline testing thoses
+// Environment variables set by Scaleway Functions runtime and used to build the execution context.
+const (
+	EnvFunctionName     = "SCW_FUNCTION_NAME"
+	EnvFunctionVersion  = "SCW_FUNCTION_VERSION"
+	EnvFunctionMemoryMB = "SCW_FUNCTION_MEMORY_LIMIT_IN_MB"
+	EnvNamespaceName    = "SCW_NAMESPACE_NAME"
+	EnvRegion           = "SCW_REGION"
+)
+
+// Default values of the execution context when the runtime environment variables are not set.
+const (
+	defaultMemoryLimitInMB = 128
+	defaultFunctionName    = "handler"
+	defaultFunctionVersion = "0.0.0"
+)
+
+// contextKey is the type of keys used to store invocation values in a context.Context.
+type contextKey int
+
+const (
+	executionContextKey contextKey = iota
+	requestIDKey
+	envReporterKey
+)
+
+// GetExecutionContext is used to create a new execution context and make it available. Values are read from the
+// environment variables set by Scaleway Functions runtime (see EnvFunctionName...), for offline testing thoses
 // values are definied by default and does not affect functions performance.
 func GetExecutionContext() ExecutionContext {
-	return ExecutionContext{
-		MemoryLimitInMB: 128,
-		FunctionName:    "handler",
-		FunctionVersion: "0.0.0",
+	execCtx := ExecutionContext{
+		MemoryLimitInMB: defaultMemoryLimitInMB,
+		FunctionName:    envOrDefault(EnvFunctionName, defaultFunctionName),
+		FunctionVersion: envOrDefault(EnvFunctionVersion, defaultFunctionVersion),
+		NamespaceName:   os.Getenv(EnvNamespaceName),
+		Region:          os.Getenv(EnvRegion),
+	}
+
+	if memory, err := strconv.Atoi(os.Getenv(EnvFunctionMemoryMB)); err == nil && memory > 0 {
+		execCtx.MemoryLimitInMB = memory
+	}
+
+	return execCtx
+}
+
+// envOrDefault returns the value of the environment variable key, or fallback if it is not set or empty.
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// WithExecutionContext returns a copy of ctx carrying the execution context of the invocation.
+func WithExecutionContext(ctx context.Context, execCtx ExecutionContext) context.Context {
+	return context.WithValue(ctx, executionContextKey, execCtx)
+}
+
+// ExecutionContextFrom returns the execution context carried by ctx, if there is none the execution context
+// returned by GetExecutionContext is used. In a ScwFuncV1 handler use the context of the request:
+//
+//	execCtx := core.ExecutionContextFrom(r.Context())
+func ExecutionContextFrom(ctx context.Context) ExecutionContext {
+	if execCtx, ok := ctx.Value(executionContextKey).(ExecutionContext); ok {
+		return execCtx
+	}
+
+	return GetExecutionContext()
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being processed.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom returns the ID of the request carried by ctx, or an empty string if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+
+	return requestID
+}
+
+// RequestIDFromHTTP returns the ID of the request, read from its context or from the X-Request-Id header set by the
+// platform infrastructure.
+func RequestIDFromHTTP(req *http.Request) string {
+	if requestID := RequestIDFrom(req.Context()); requestID != "" {
+		return requestID
 	}
+
+	return req.Header.Get(requestIDHeaderKey)
 }
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionContextFrom(t *testing.T) {
+	t.Parallel()
+
+	execCtx := ExecutionContext{
+		MemoryLimitInMB: 256,
+		FunctionName:    "my-function",
+		FunctionVersion: "1.2.3",
+	}
+
+	ctx := WithExecutionContext(context.Background(), execCtx)
+
+	assert.Equal(t, execCtx, ExecutionContextFrom(ctx))
+	assert.Equal(t, GetExecutionContext(), ExecutionContextFrom(context.Background()))
+}
+
+func TestRequestIDFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithRequestID(context.Background(), "request-id")
+
+	assert.Equal(t, "request-id", RequestIDFrom(ctx))
+	assert.Empty(t, RequestIDFrom(context.Background()))
+}
+
+func TestRequestIDFromHTTP(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", http.NoBody)
+	require.NoError(t, err)
+
+	req.Header.Set("X-Request-Id", "from-header")
+	assert.Equal(t, "from-header", RequestIDFromHTTP(req))
+
+	req = req.WithContext(WithRequestID(req.Context(), "from-context"))
+	assert.Equal(t, "from-context", RequestIDFromHTTP(req))
+}
+
+func TestGetExecutionContextEnv(t *testing.T) {
+	t.Setenv(EnvFunctionName, "my-function")
+	t.Setenv(EnvFunctionVersion, "1.0.0")
+	t.Setenv(EnvFunctionMemoryMB, "512")
+	t.Setenv(EnvNamespaceName, "my-namespace")
+	t.Setenv(EnvRegion, "fr-par")
+
+	assert.Equal(t, ExecutionContext{
+		MemoryLimitInMB: 512,
+		FunctionName:    "my-function",
+		FunctionVersion: "1.0.0",
+		NamespaceName:   "my-namespace",
+		Region:          "fr-par",
+	}, GetExecutionContext())
+}
+
+func TestGetExecutionContextFallback(t *testing.T) {
+	t.Setenv(EnvFunctionName, "")
+	t.Setenv(EnvFunctionVersion, "")
+	t.Setenv(EnvFunctionMemoryMB, "not a number")
+	t.Setenv(EnvNamespaceName, "")
+	t.Setenv(EnvRegion, "")
+
+	assert.Equal(t, ExecutionContext{
+		MemoryLimitInMB: 128,
+		FunctionName:    "handler",
+		FunctionVersion: "0.0.0",
+	}, GetExecutionContext())
+}
+
+func TestGetenvReporter(t *testing.T) {
+	t.Setenv("CORE_TEST_ENV", "value")
+
+	var reported []string
+
+	ctx := WithEnvReporter(context.Background(), func(key string) {
+		reported = append(reported, key)
+	})
+
+	assert.Equal(t, "value", Getenv(ctx, "CORE_TEST_ENV"))
+
+	_, ok := LookupEnv(ctx, "CORE_TEST_UNSET")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"CORE_TEST_ENV", "CORE_TEST_UNSET"}, reported)
+	assert.Equal(t, "value", Getenv(context.Background(), "CORE_TEST_ENV"))
+}
@@ -0,0 +1,212 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS headers.
+const (
+	HeaderAllowOrigin      = "Access-Control-Allow-Origin"
+	HeaderAllowMethods     = "Access-Control-Allow-Methods"
+	HeaderAllowHeaders     = "Access-Control-Allow-Headers"
+	HeaderAllowCredentials = "Access-Control-Allow-Credentials"
+	HeaderExposeHeaders    = "Access-Control-Expose-Headers"
+	HeaderMaxAge           = "Access-Control-Max-Age"
+	HeaderRequestMethod    = "Access-Control-Request-Method"
+	HeaderRequestHeaders   = "Access-Control-Request-Headers"
+
+	headerOrigin = "Origin"
+	headerVary   = "Vary"
+)
+
+// corsWildcard allows any origin, method or header.
+const corsWildcard = "*"
+
+// CORSPolicy describes the Cross-Origin Resource Sharing headers of responses, see
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/CORS.
+type CORSPolicy struct {
+	// AllowedOrigins lists the origins allowed to call the function, "*" allows any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods allowed in preflight requests, the requested method is allowed when empty.
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers allowed in preflight requests, "*" allows any header.
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers readable by the caller.
+	ExposedHeaders []string
+
+	// AllowCredentials allows requests with credentials, the origin is then returned instead of "*".
+	AllowCredentials bool
+
+	// MaxAge is the duration preflight responses can be cached, it is not sent when zero.
+	MaxAge time.Duration
+}
+
+// DefaultCORSPolicy returns the policy applied by the platform when the function does not set CORS headers: any
+// origin is allowed with the Content-Type header.
+func DefaultCORSPolicy() CORSPolicy {
+	return CORSPolicy{
+		AllowedOrigins: []string{corsWildcard},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+}
+
+// AllowsOrigin returns true if requests from origin are allowed by the policy.
+func (p *CORSPolicy) AllowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == corsWildcard || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPreflight returns true if req is a CORS preflight request.
+func (p *CORSPolicy) IsPreflight(req *http.Request) bool {
+	return req.Method == http.MethodOptions && req.Header.Get(headerOrigin) != "" &&
+		req.Header.Get(HeaderRequestMethod) != ""
+}
+
+// SetHeaders sets the CORS headers of the response to req in header. Headers already present in header are kept, so
+// a handler setting its own CORS headers takes precedence. Nothing is set when the origin is not allowed.
+func (p *CORSPolicy) SetHeaders(req *http.Request, header http.Header) {
+	allowOrigin, ok := p.allowOrigin(req.Header.Get(headerOrigin))
+	if !ok {
+		return
+	}
+
+	if allowOrigin != corsWildcard {
+		header.Add(headerVary, headerOrigin)
+	}
+
+	setDefault(header, HeaderAllowOrigin, allowOrigin)
+
+	if p.AllowCredentials {
+		setDefault(header, HeaderAllowCredentials, "true")
+	}
+
+	if len(p.AllowedMethods) > 0 {
+		setDefault(header, HeaderAllowMethods, strings.Join(p.AllowedMethods, ", "))
+	}
+
+	if len(p.AllowedHeaders) > 0 {
+		setDefault(header, HeaderAllowHeaders, strings.Join(p.AllowedHeaders, ", "))
+	}
+
+	if len(p.ExposedHeaders) > 0 {
+		setDefault(header, HeaderExposeHeaders, strings.Join(p.ExposedHeaders, ", "))
+	}
+}
+
+// WritePreflight answers the preflight request req with a 204 No Content response. The methods and headers
+// requested are allowed according to the policy, the response has no CORS header when they are not.
+func (p *CORSPolicy) WritePreflight(resp http.ResponseWriter, req *http.Request) {
+	header := resp.Header()
+	header.Add(headerVary, headerOrigin)
+	header.Add(headerVary, HeaderRequestMethod)
+	header.Add(headerVary, HeaderRequestHeaders)
+
+	allowOrigin, ok := p.allowOrigin(req.Header.Get(headerOrigin))
+	if ok && p.allowsMethod(req.Header.Get(HeaderRequestMethod)) && p.allowsHeaders(req.Header.Get(HeaderRequestHeaders)) {
+		header.Set(HeaderAllowOrigin, allowOrigin)
+		header.Set(HeaderAllowMethods, req.Header.Get(HeaderRequestMethod))
+
+		if requested := req.Header.Get(HeaderRequestHeaders); requested != "" {
+			header.Set(HeaderAllowHeaders, requested)
+		}
+
+		if p.AllowCredentials {
+			header.Set(HeaderAllowCredentials, "true")
+		}
+
+		if p.MaxAge > 0 {
+			header.Set(HeaderMaxAge, strconv.Itoa(int(p.MaxAge.Seconds())))
+		}
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// Handler returns a handler applying the policy to responses of next and answering preflight requests.
+func (p *CORSPolicy) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if p.IsPreflight(req) {
+			p.WritePreflight(resp, req)
+
+			return
+		}
+
+		p.SetHeaders(req, resp.Header())
+
+		next(resp, req)
+	}
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for origin, and false if the origin is
+// not allowed. Requests without origin are only given the header when any origin is allowed.
+func (p *CORSPolicy) allowOrigin(origin string) (string, bool) {
+	wildcard := false
+
+	for _, allowed := range p.AllowedOrigins {
+		wildcard = wildcard || allowed == corsWildcard
+	}
+
+	switch {
+	case wildcard && (!p.AllowCredentials || origin == ""):
+		return corsWildcard, true
+	case origin != "" && p.AllowsOrigin(origin):
+		return origin, true
+	default:
+		return "", false
+	}
+}
+
+// allowsMethod returns true if the method requested by a preflight request is allowed.
+func (p *CORSPolicy) allowsMethod(method string) bool {
+	if len(p.AllowedMethods) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.AllowedMethods {
+		if allowed == corsWildcard || strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsHeaders returns true if all the headers requested by a preflight request are allowed.
+func (p *CORSPolicy) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+
+		for _, allowedHeader := range p.AllowedHeaders {
+			allowed = allowed || allowedHeader == corsWildcard || strings.EqualFold(allowedHeader, header)
+		}
+
+		if !allowed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setDefault sets the header key to value unless it is already present.
+func setDefault(header http.Header, key, value string) {
+	if header.Get(key) == "" {
+		header.Set(key, value)
+	}
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSPolicySetHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   CORSPolicy
+		origin   string
+		preset   http.Header
+		expected http.Header
+	}{
+		{
+			name:   "default policy",
+			policy: DefaultCORSPolicy(),
+			expected: http.Header{
+				HeaderAllowOrigin:  {"*"},
+				HeaderAllowHeaders: {"Content-Type"},
+			},
+		},
+		{
+			name:     "allowed origin",
+			policy:   CORSPolicy{AllowedOrigins: []string{"https://a.com", "https://b.com"}, ExposedHeaders: []string{"X-Total"}},
+			origin:   "https://b.com",
+			expected: http.Header{HeaderAllowOrigin: {"https://b.com"}, HeaderExposeHeaders: {"X-Total"}, "Vary": {"Origin"}},
+		},
+		{
+			name:     "origin not allowed",
+			policy:   CORSPolicy{AllowedOrigins: []string{"https://a.com"}},
+			origin:   "https://evil.com",
+			expected: http.Header{},
+		},
+		{
+			name:     "credentials with any origin",
+			policy:   CORSPolicy{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			origin:   "https://a.com",
+			expected: http.Header{HeaderAllowOrigin: {"https://a.com"}, HeaderAllowCredentials: {"true"}, "Vary": {"Origin"}},
+		},
+		{
+			name:     "handler headers take precedence",
+			policy:   DefaultCORSPolicy(),
+			preset:   http.Header{HeaderAllowOrigin: {"https://a.com"}},
+			expected: http.Header{HeaderAllowOrigin: {"https://a.com"}, HeaderAllowHeaders: {"Content-Type"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			header := http.Header{}
+			SetHeaders(tt.preset, header)
+
+			tt.policy.SetHeaders(req, header)
+
+			assert.Equal(t, tt.expected, header)
+		})
+	}
+}
+
+func TestCORSPolicyPreflight(t *testing.T) {
+	t.Parallel()
+
+	policy := CORSPolicy{
+		AllowedOrigins:   []string{"https://a.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+
+	preflight := func(origin, method, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/", http.NoBody)
+		req.Header.Set("Origin", origin)
+		req.Header.Set(HeaderRequestMethod, method)
+		req.Header.Set(HeaderRequestHeaders, headers)
+
+		assert.True(t, policy.IsPreflight(req))
+
+		recorder := httptest.NewRecorder()
+		policy.Handler(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("handler must not be called for preflight requests")
+		})(recorder, req)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+		return recorder
+	}
+
+	allowed := preflight("https://a.com", http.MethodPut, "content-type, authorization")
+	assert.Equal(t, "https://a.com", allowed.Header().Get(HeaderAllowOrigin))
+	assert.Equal(t, http.MethodPut, allowed.Header().Get(HeaderAllowMethods))
+	assert.Equal(t, "content-type, authorization", allowed.Header().Get(HeaderAllowHeaders))
+	assert.Equal(t, "true", allowed.Header().Get(HeaderAllowCredentials))
+	assert.Equal(t, "3600", allowed.Header().Get(HeaderMaxAge))
+
+	for _, denied := range []*httptest.ResponseRecorder{
+		preflight("https://evil.com", http.MethodPut, ""),
+		preflight("https://a.com", http.MethodDelete, ""),
+		preflight("https://a.com", http.MethodGet, "X-Custom"),
+	} {
+		assert.Empty(t, denied.Header().Get(HeaderAllowOrigin))
+	}
+
+	req := httptest.NewRequest(http.MethodOptions, "/", http.NoBody)
+	assert.False(t, policy.IsPreflight(req))
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"context"
+	"os"
+)
+
+// EnvReporter is called with the key of environment variables read with Getenv or LookupEnv.
+type EnvReporter func(key string)
+
+// WithEnvReporter returns a copy of ctx in which reads of environment variables are reported, the local server uses
+// it to warn about variables not declared in the configuration of the function.
+func WithEnvReporter(ctx context.Context, reporter EnvReporter) context.Context {
+	return context.WithValue(ctx, envReporterKey, reporter)
+}
+
+// Getenv retrieves the value of the environment variable key like os.Getenv. Reading the configuration of your
+// function with Getenv lets the local server check it is declared.
+func Getenv(ctx context.Context, key string) string {
+	value, _ := LookupEnv(ctx, key)
+
+	return value
+}
+
+// LookupEnv retrieves the value of the environment variable key like os.LookupEnv, see Getenv.
+func LookupEnv(ctx context.Context, key string) (string, bool) {
+	if reporter, ok := ctx.Value(envReporterKey).(EnvReporter); ok && reporter != nil {
+		reporter(key)
+	}
+
+	return os.LookupEnv(key)
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HeaderResponseEnvelope marks a response whose body is a response envelope (statusCode, headers, body and
+// isBase64Encoded) to interpret, see WriteResponse. Without it the body is sent to the caller as is.
+const HeaderResponseEnvelope = "X-Scw-Response-Envelope"
+
+// Fields of a response envelope.
+const (
+	envelopeStatusCode      = "statusCode"
+	envelopeHeaders         = "headers"
+	envelopeBody            = "body"
+	envelopeIsBase64Encoded = "isBase64Encoded"
+)
+
+// ErrInvalidResponseEnvelope is returned when a response marked as an envelope can not be interpreted.
+var ErrInvalidResponseEnvelope = errors.New("invalid response envelope")
+
+// WriteResponse writes response as the response of a http.HandlerFunc handler, marked as an envelope so its status
+// code, headers and body are interpreted by the platform. Use it to return a base64 encoded body or the same
+// response as a ScwFuncV2 handler.
+func WriteResponse(w http.ResponseWriter, response ResponseHTTP) error {
+	if response.StatusCode == 0 {
+		response.StatusCode = http.StatusOK
+	}
+
+	envelope, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidResponseEnvelope, err)
+	}
+
+	if _, err := ParseResponseEnvelope(envelope); err != nil {
+		return err
+	}
+
+	w.Header().Set(HeaderResponseEnvelope, "true")
+	w.Header().Set(contentTypeHeaderKey, "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = w.Write(envelope)
+
+	return err
+}
+
+// IsResponseEnvelope returns true if the headers of a response mark its body as a response envelope.
+func IsResponseEnvelope(header http.Header) bool {
+	isEnvelope, _ := strconv.ParseBool(header.Get(HeaderResponseEnvelope))
+
+	return isEnvelope
+}
+
+// ParseResponseEnvelope strictly validates and decodes a response envelope, errors describe what to fix:
+//   - the envelope is a JSON object with only statusCode, headers, body and isBase64Encoded fields,
+//   - statusCode, when set, is a number between 100 and 599,
+//   - headers map names to a string or a list of strings,
+//   - body is a base64 encoded string when isBase64Encoded is true.
+func ParseResponseEnvelope(envelope []byte) (ResponseHTTP, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(envelope, &fields); err != nil {
+		return ResponseHTTP{}, fmt.Errorf("%w: it must be a JSON object with %s fields: %w", ErrInvalidResponseEnvelope,
+			envelopeFieldNames(), err)
+	}
+
+	for field := range fields {
+		switch field {
+		case envelopeStatusCode, envelopeHeaders, envelopeBody, envelopeIsBase64Encoded:
+		default:
+			return ResponseHTTP{}, fmt.Errorf("%w: unknown field %q, expected one of %s (fields are case sensitive)",
+				ErrInvalidResponseEnvelope, field, envelopeFieldNames())
+		}
+	}
+
+	response := ResponseHTTP{Body: fields[envelopeBody]}
+
+	if err := parseEnvelopeStatusCode(fields[envelopeStatusCode], &response); err != nil {
+		return ResponseHTTP{}, err
+	}
+
+	if err := parseEnvelopeHeaders(fields[envelopeHeaders], &response); err != nil {
+		return ResponseHTTP{}, err
+	}
+
+	if raw, ok := fields[envelopeIsBase64Encoded]; ok && !isJSONNull(raw) {
+		if err := json.Unmarshal(raw, &response.IsBase64Encoded); err != nil {
+			return ResponseHTTP{}, fmt.Errorf("%w: %s must be a boolean, got %s", ErrInvalidResponseEnvelope,
+				envelopeIsBase64Encoded, raw)
+		}
+	}
+
+	if response.IsBase64Encoded && len(response.Body) > 0 {
+		if _, err := response.DecodedBody(); err != nil {
+			return ResponseHTTP{}, fmt.Errorf("%w: %s must be a base64 encoded string when %s is true: %w",
+				ErrInvalidResponseEnvelope, envelopeBody, envelopeIsBase64Encoded, err)
+		}
+	}
+
+	return response, nil
+}
+
+// parseEnvelopeStatusCode decodes the status code of an envelope into response.
+func parseEnvelopeStatusCode(raw json.RawMessage, response *ResponseHTTP) error {
+	const (
+		minStatusCode = 100
+		maxStatusCode = 599
+	)
+
+	if len(raw) == 0 || isJSONNull(raw) {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, &response.StatusCode); err != nil {
+		return fmt.Errorf("%w: %s must be an integer, got %s", ErrInvalidResponseEnvelope, envelopeStatusCode, raw)
+	}
+
+	if response.StatusCode < minStatusCode || response.StatusCode > maxStatusCode {
+		return fmt.Errorf("%w: %s %d is not a valid HTTP status code (%d-%d)", ErrInvalidResponseEnvelope,
+			envelopeStatusCode, response.StatusCode, minStatusCode, maxStatusCode)
+	}
+
+	return nil
+}
+
+// parseEnvelopeHeaders decodes the headers of an envelope into response, values can be strings or lists of strings.
+func parseEnvelopeHeaders(raw json.RawMessage, response *ResponseHTTP) error {
+	response.Headers = map[string][]string{}
+
+	if len(raw) == 0 || isJSONNull(raw) {
+		return nil
+	}
+
+	headers := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &headers); err != nil {
+		return fmt.Errorf("%w: %s must be an object, got %s", ErrInvalidResponseEnvelope, envelopeHeaders, raw)
+	}
+
+	for key, value := range headers {
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			response.Headers[key] = []string{single}
+
+			continue
+		}
+
+		var values []string
+		if err := json.Unmarshal(value, &values); err != nil {
+			return fmt.Errorf("%w: header %q must be a string or a list of strings, got %s", ErrInvalidResponseEnvelope,
+				key, value)
+		}
+
+		if len(values) > 0 {
+			response.Headers[key] = values
+		}
+	}
+
+	return nil
+}
+
+// isJSONNull returns true if raw is the JSON null value.
+func isJSONNull(raw json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// envelopeFieldNames lists the fields of an envelope, for error messages.
+func envelopeFieldNames() string {
+	names := []string{envelopeStatusCode, envelopeHeaders, envelopeBody, envelopeIsBase64Encoded}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResponseEnvelope(t *testing.T) {
+	t.Parallel()
+
+	response, err := ParseResponseEnvelope([]byte(`{
+		"statusCode": 201,
+		"headers": {"flat": "value", "array": ["val1", "val2"], "empty": []},
+		"body": {"key": "value"},
+		"isBase64Encoded": false
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, map[string][]string{"flat": {"value"}, "array": {"val1", "val2"}}, response.Headers)
+	assert.JSONEq(t, `{"key": "value"}`, string(response.Body))
+
+	response, err = ParseResponseEnvelope([]byte(`{"body": "text"}`))
+	require.NoError(t, err)
+	assert.Zero(t, response.StatusCode)
+}
+
+func TestParseResponseEnvelopeErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		envelope string
+		message  string
+	}{
+		{name: "not an object", envelope: `["body"]`, message: "it must be a JSON object"},
+		{name: "not JSON", envelope: `statusCode: 200`, message: "it must be a JSON object"},
+		{name: "unknown field", envelope: `{"status": 200}`, message: `unknown field "status"`},
+		{name: "wrong case", envelope: `{"StatusCode": 200}`, message: "case sensitive"},
+		{name: "status code type", envelope: `{"statusCode": "200"}`, message: "statusCode must be an integer"},
+		{name: "status code range", envelope: `{"statusCode": 1000}`, message: "not a valid HTTP status code"},
+		{name: "headers type", envelope: `{"headers": ["a"]}`, message: "headers must be an object"},
+		{name: "header value", envelope: `{"headers": {"a": 1}}`, message: `header "a" must be a string or a list`},
+		{name: "base64 flag", envelope: `{"isBase64Encoded": "yes"}`, message: "isBase64Encoded must be a boolean"},
+		{name: "base64 body", envelope: `{"isBase64Encoded": true, "body": "not base64!"}`, message: "base64 encoded string"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseResponseEnvelope([]byte(tt.envelope))
+			assert.ErrorIs(t, err, ErrInvalidResponseEnvelope)
+			assert.ErrorContains(t, err, tt.message)
+		})
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("X-Custom", "custom")
+
+	require.NoError(t, WriteResponse(recorder, ResponseHTTP{
+		StatusCode: http.StatusAccepted,
+		Body:       json.RawMessage(`"accepted"`),
+		Headers:    map[string][]string{"Content-Type": {"text/plain"}},
+	}))
+
+	//nolint:bodyclose
+	resp, err := GetResponse(recorder.Result())
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, []string{"text/plain"}, resp.Headers["Content-Type"])
+	assert.Equal(t, []string{"custom"}, resp.Headers["X-Custom"])
+	assert.NotContains(t, resp.Headers, HeaderResponseEnvelope)
+
+	body, err := resp.DecodedBody()
+	require.NoError(t, err)
+	assert.Equal(t, "accepted", string(body))
+
+	err = WriteResponse(httptest.NewRecorder(), ResponseHTTP{StatusCode: 42})
+	assert.ErrorIs(t, err, ErrInvalidResponseEnvelope)
+}
@@ -3,9 +3,11 @@ package core
 import (
 	"encoding/base64"
 	"encoding/json"
+	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 const headerContentLen = "Content-Length"
@@ -39,37 +41,80 @@ type APIGatewayProxyRequestContext struct {
 	APIID        string                 `json:"apiId"` // The API Gateway rest API Id
 }
 
-// FormatEventHTTP converts a http.Request to internal APIGatewayProxyRequest object.
+// DefaultBinaryContentTypes lists the media types of request bodies considered binary, they are base64 encoded in
+// events. A type ending with "/*" matches all its subtypes.
+var DefaultBinaryContentTypes = []string{
+	"application/octet-stream",
+	"application/pdf",
+	"application/zip",
+	"application/gzip",
+	"application/protobuf",
+	"application/x-protobuf",
+	"application/grpc",
+	"image/*",
+	"audio/*",
+	"video/*",
+	"font/*",
+}
+
+// EventFormatter converts HTTP requests to events given to handlers.
+type EventFormatter struct {
+	// BinaryContentTypes lists the media types of bodies base64 encoded in events, see DefaultBinaryContentTypes.
+	BinaryContentTypes []string
+}
+
+// FormatEventHTTP converts a http.Request to internal APIGatewayProxyRequest object, binary bodies are detected with
+// DefaultBinaryContentTypes.
 func FormatEventHTTP(req *http.Request, bodyBytes []byte) APIGatewayProxyRequest {
-	queryParameters := map[string]string{}
-	for key, value := range req.URL.Query() {
+	formatter := EventFormatter{BinaryContentTypes: DefaultBinaryContentTypes}
+
+	return formatter.Format(req, bodyBytes)
+}
+
+// Format converts a http.Request to internal APIGatewayProxyRequest object. Headers and query parameters are given
+// with all their values in MultiValueHeaders and MultiValueQueryStringParameters, Headers joins values with commas
+// and QueryStringParameters keeps the last value. As the platform does, binary bodies are base64 encoded in the Body
+// of the event and IsBase64Encoded is set. A body is binary when its Content-Type is
+// one of BinaryContentTypes, or when it is not valid UTF-8.
+func (f *EventFormatter) Format(req *http.Request, bodyBytes []byte) APIGatewayProxyRequest {
+	query := req.URL.Query()
+
+	queryParameters := make(map[string]string, len(query))
+	multiValueQueryParameters := make(map[string][]string, len(query))
+
+	for key, value := range query {
 		queryParameters[key] = value[len(value)-1]
+		multiValueQueryParameters[key] = value
 	}
 
 	input := string(bodyBytes)
-	isBase64Encoded := true
+	isBase64Encoded := len(bodyBytes) > 0 &&
+		(f.IsBinary(req.Header.Get(contentTypeHeaderKey)) || !utf8.Valid(bodyBytes))
 
-	_, err := base64.StdEncoding.DecodeString(input)
-	if err != nil {
-		isBase64Encoded = false
+	if isBase64Encoded {
+		input = base64.StdEncoding.EncodeToString(bodyBytes)
 	}
 
 	flatHeader := make(map[string]string, len(req.Header))
+	multiValueHeader := make(map[string][]string, len(req.Header))
 
 	for key, val := range req.Header {
 		if len(val) > 0 {
 			flatHeader[key] = strings.Join(val, ",")
+			multiValueHeader[key] = append([]string(nil), val...)
 		}
 	}
 
 	return APIGatewayProxyRequest{
-		Path:                  req.URL.Path,
-		HTTPMethod:            req.Method,
-		Headers:               flatHeader,
-		QueryStringParameters: queryParameters,
-		StageVariables:        map[string]string{},
-		Body:                  input,
-		IsBase64Encoded:       isBase64Encoded,
+		Path:                            req.URL.Path,
+		HTTPMethod:                      req.Method,
+		Headers:                         flatHeader,
+		MultiValueHeaders:               multiValueHeader,
+		QueryStringParameters:           queryParameters,
+		MultiValueQueryStringParameters: multiValueQueryParameters,
+		StageVariables:                  map[string]string{},
+		Body:                            input,
+		IsBase64Encoded:                 isBase64Encoded,
 		RequestContext: APIGatewayProxyRequestContext{
 			Stage:      "",
 			HTTPMethod: req.Method,
@@ -77,6 +122,29 @@ func FormatEventHTTP(req *http.Request, bodyBytes []byte) APIGatewayProxyRequest
 	}
 }
 
+// IsBinary returns true if contentType is one of the binary content types of the formatter, parameters like charset
+// are ignored.
+func (f *EventFormatter) IsBinary(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, binaryType := range f.BinaryContentTypes {
+		binaryType = strings.ToLower(binaryType)
+
+		if mediaType == binaryType {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(binaryType, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HydrateHttpResponse will try to fill the response writer with content of body. Addtionaly it adds
 // Content-Length header, this has to by done always before any call to Write on the body.
 func HydrateHTTPResponse(resp http.ResponseWriter, body json.RawMessage, statusCode int) {
@@ -84,14 +152,16 @@ func HydrateHTTPResponse(resp http.ResponseWriter, body json.RawMessage, statusC
 	var bodyString string
 
 	if err := json.Unmarshal(body, &bodyString); err == nil {
-		resp.Header().Set(headerContentLen, strconv.Itoa(len(bodyString)))
-		resp.WriteHeader(statusCode)
-
-		_, _ = resp.Write([]byte(bodyString))
+		WriteHTTPResponse(resp, []byte(bodyString), statusCode)
 
 		return
 	}
 
+	WriteHTTPResponse(resp, body, statusCode)
+}
+
+// WriteHTTPResponse writes body as is with its Content-Length header and statusCode.
+func WriteHTTPResponse(resp http.ResponseWriter, body []byte, statusCode int) {
 	resp.Header().Set(headerContentLen, strconv.Itoa(len(body)))
 	resp.WriteHeader(statusCode)
 
@@ -102,23 +172,8 @@ func IsRejectedRequest(request *http.Request) bool {
 	return request.URL.Path == "/favicon.ico" || request.URL.Path == "/robots.txt"
 }
 
+// SetHeaders adds the values of input headers to output headers.
 func SetHeaders(input, output http.Header) {
-	const (
-		originCORS  = "access-control-allow-origin"
-		headersCORS = "access-control-allow-headers"
-	)
-	// first loop to reset CORS values if necessary
-	// This prevent this kind of header errors : access-control-allow-origin: *,*
-	for key := range input {
-		lowerKey := strings.ToLower(key)
-
-		if lowerKey == originCORS {
-			output.Del(originCORS)
-		} else if lowerKey == headersCORS {
-			output.Del(headersCORS)
-		}
-	}
-
 	for key, values := range input {
 		for idx := range values {
 			output.Add(key, values[idx])
@@ -36,6 +36,11 @@ func TestFormatEventHttp(t *testing.T) {
 	_, ok := ret.Headers["empty"]
 	assert.False(t, ok)
 
+	assert.Equal(t, []string{"val1", "val2"}, ret.MultiValueHeaders["array"])
+	assert.Equal(t, []string{"val1,val2"}, ret.MultiValueHeaders["comma"])
+	_, ok = ret.MultiValueHeaders["empty"]
+	assert.False(t, ok)
+
 	assert.Equal(t, "127.0.0.1", req.URL.Path)
 
 	assert.Equal(t, http.MethodPost, req.Method)
@@ -48,33 +53,90 @@ func TestFormatEventHttp(t *testing.T) {
 func TestFormatEventHttpBase64(t *testing.T) {
 	t.Parallel()
 
-	testHeaders := http.Header{
-		"array":  []string{"val1", "val2"},
-		"comma":  []string{"val1,val2"},
-		"single": []string{"val1"},
-		"empty":  []string{},
+	rawBody := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		base64      bool
+	}{
+		{name: "plain text looking like base64", contentType: "text/plain", body: []byte("test"), base64: false},
+		{name: "base64 text without content type", body: []byte(base64.StdEncoding.EncodeToString([]byte("sample")))},
+		{name: "empty body", contentType: "application/octet-stream", body: []byte{}, base64: false},
+		{name: "json", contentType: "application/json; charset=utf-8", body: []byte(`{"key":"value"}`), base64: false},
+		{name: "binary content type", contentType: "application/x-protobuf", body: []byte("text"), base64: true},
+		{name: "binary content type family", contentType: "image/png", body: rawBody, base64: true},
+		{name: "invalid utf-8 without content type", body: rawBody, base64: true},
 	}
 
-	req := http.Request{
-		Header: testHeaders,
-		// to avoid error in url generation
-		URL:    &url.URL{Path: "127.0.0.1"},
-		Method: http.MethodPost,
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := http.Request{
+				Header: http.Header{},
+				URL:    &url.URL{Path: "/"},
+				Method: http.MethodPost,
+			}
+
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+
+			ret := FormatEventHTTP(&req, tt.body)
+
+			assert.Equal(t, tt.base64, ret.IsBase64Encoded)
+
+			if !tt.base64 {
+				assert.Equal(t, string(tt.body), ret.Body)
+
+				return
+			}
+
+			decoded, err := base64.StdEncoding.DecodeString(ret.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, decoded)
+		})
 	}
+}
 
-	rawBodyValue := []byte("base64sample")
-	// create a base64 string :
-	b64content := base64.StdEncoding.EncodeToString(rawBodyValue)
+func TestEventFormatterBinaryContentTypes(t *testing.T) {
+	t.Parallel()
 
-	testBody := []byte(b64content)
+	formatter := EventFormatter{BinaryContentTypes: []string{"application/vnd.custom", "model/*"}}
 
-	ret := FormatEventHTTP(&req, testBody)
+	assert.True(t, formatter.IsBinary("application/vnd.custom"))
+	assert.True(t, formatter.IsBinary("Model/GLTF-Binary"))
+	assert.False(t, formatter.IsBinary("image/png"))
+	assert.False(t, formatter.IsBinary("invalid;;"))
+	assert.False(t, formatter.IsBinary(""))
+
+	req := http.Request{
+		Header: http.Header{"Content-Type": {"application/vnd.custom"}},
+		URL:    &url.URL{Path: "/"},
+		Method: http.MethodPost,
+	}
 
+	ret := formatter.Format(&req, []byte("payload"))
 	assert.True(t, ret.IsBase64Encoded)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("payload")), ret.Body)
+}
+
+func TestFormatEventHttpMultiValueQuery(t *testing.T) {
+	t.Parallel()
+
+	req := http.Request{
+		Header: http.Header{},
+		URL:    &url.URL{Path: "/", RawQuery: "id=1&id=2&single=value"},
+		Method: http.MethodGet,
+	}
 
-	assert.Equal(t, b64content, ret.Body)
+	ret := FormatEventHTTP(&req, nil)
 
-	b64decoded, err := base64.StdEncoding.DecodeString(ret.Body)
-	require.NoError(t, err)
-	assert.Equal(t, b64decoded, rawBodyValue)
+	assert.Equal(t, "2", ret.QueryStringParameters["id"])
+	assert.Equal(t, []string{"1", "2"}, ret.MultiValueQueryStringParameters["id"])
+	assert.Equal(t, []string{"value"}, ret.MultiValueQueryStringParameters["single"])
 }
@@ -88,20 +88,29 @@ func (fn *FunctionInvoker) StreamRequest(reqBody CoreRuntimeRequest) (*http.Requ
 	// When sending the request to the sub runtime, the request is always in JSON format.
 	request.Header.Set(contentTypeHeaderKey, "application/json")
 
-	for key, values := range event.Headers {
+	// Multi-value headers carry all the values of a header, the flattened value is only used when they are missing.
+	multiValueKeys := make(map[string]bool, len(event.MultiValueHeaders))
+
+	for key, values := range event.MultiValueHeaders {
 		if strings.EqualFold(key, contentTypeHeaderKey) {
 			continue
 		}
 
-		request.Header.Set(key, values)
-	}
+		multiValueKeys[http.CanonicalHeaderKey(key)] = true
 
-	for key, values := range event.MultiValueHeaders {
 		for idx := range values {
 			request.Header.Add(key, values[idx])
 		}
 	}
 
+	for key, values := range event.Headers {
+		if strings.EqualFold(key, contentTypeHeaderKey) || multiValueKeys[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+
+		request.Header.Set(key, values)
+	}
+
 	if event.Headers[userAgentHeaderKey] != "" {
 		request.Header.Set(userAgentHeaderKey, event.Headers[userAgentHeaderKey])
 	}
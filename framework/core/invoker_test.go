@@ -110,3 +110,28 @@ func TestStreamExecute(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, resp)
 }
+
+func TestStreamRequestMultiValueHeaders(t *testing.T) {
+	t.Parallel()
+
+	invoker, err := NewInvoker("", "", "", "", "http://localhost", false)
+	require.NoError(t, err)
+
+	req, err := invoker.StreamRequest(CoreRuntimeRequest{Event: APIGatewayProxyRequest{
+		Path: "/",
+		Headers: map[string]string{
+			"Accept":       "text/html,application/json",
+			"Content-Type": "text/plain",
+			"X-Flat":       "flat",
+		},
+		MultiValueHeaders: map[string][]string{
+			"Accept":       {"text/html", "application/json"},
+			"Content-Type": {"text/plain"},
+		},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"text/html", "application/json"}, req.Header.Values("Accept"))
+	assert.Equal(t, []string{"application/json"}, req.Header.Values("Content-Type"))
+	assert.Equal(t, "flat", req.Header.Get("X-Flat"))
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"unicode/utf8"
 )
 
 var (
@@ -22,6 +23,9 @@ type ResponseHTTP struct {
 	Body            json.RawMessage     `json:"body"`
 	Headers         map[string][]string `json:"headers"`
 	IsBase64Encoded bool                `json:"isBase64Encoded"`
+
+	// raw is set when Body holds the bytes to send as is rather than JSON, see GetResponse.
+	raw bool
 }
 
 // IsJSON returns true if the input is a valid JSON message.
@@ -30,7 +34,9 @@ func IsJSON(bytes []byte) bool {
 	return json.Unmarshal(bytes, &js) == nil
 }
 
-// GetResponse Transform a response string into an HTTP Response structure.
+// GetResponse Transform a response string into an HTTP Response structure. The body is sent to the caller as is,
+// unless the response is marked with HeaderResponseEnvelope: its body is then strictly parsed as a response envelope,
+// see ParseResponseEnvelope. Bodies that are not valid UTF-8 are base64 encoded, see ResponseHTTP.DecodedBody.
 func GetResponse(response *http.Response) (*ResponseHTTP, error) {
 	if response == nil {
 		return nil, ErrRespEmpty
@@ -39,6 +45,11 @@ func GetResponse(response *http.Response) (*ResponseHTTP, error) {
 	handlerResponse := ResponseHTTP{
 		Headers:    response.Header,
 		StatusCode: response.StatusCode,
+		raw:        true,
+	}
+
+	if handlerResponse.Headers == nil {
+		handlerResponse.Headers = http.Header{}
 	}
 
 	// Read body content
@@ -50,49 +61,41 @@ func GetResponse(response *http.Response) (*ResponseHTTP, error) {
 			return nil, ErrInvalidHTTPResponseFormat
 		}
 
-		// Unmarshalling into handler response can modify original body so we keep it in new variable
-		bbCopy := make([]byte, len(bodyBytes))
-		copy(bbCopy, bodyBytes)
-
 		handlerResponse.Body = bodyBytes
 
-		if IsJSON(bbCopy) {
-			_ = json.Unmarshal(bbCopy, &handlerResponse)
-
-			// first we try to find headers as map[string]string
-			type headerFlat struct {
-				Headers map[string]string `json:"headers"`
+		switch {
+		case IsResponseEnvelope(response.Header):
+			envelope, err := ParseResponseEnvelope(bodyBytes)
+			if err != nil {
+				return nil, err
 			}
 
-			hflat := headerFlat{}
+			// The envelope defines the response, the content type of the response carrying it is not sent.
+			headers := http.Header(handlerResponse.Headers).Clone()
+			headers.Del(HeaderResponseEnvelope)
+			headers.Del(contentTypeHeaderKey)
 
-			_ = json.Unmarshal(bbCopy, &hflat)
-
-			for key, val := range hflat.Headers {
-				handlerResponse.Headers[key] = []string{val}
-			}
-
-			// then we try to find headers as map[string][]string
-			type headerSlice struct {
-				Headers map[string][]string `json:"headers"`
+			for key, values := range envelope.Headers {
+				headers[key] = values
 			}
 
-			hslice := headerSlice{}
-			_ = json.Unmarshal(bbCopy, &hslice)
-
-			// if headers are both definied as "flat" or "slice", the "slice" version will override "flat" version
-			for key, val := range hslice.Headers {
-				if len(val) == 0 {
-					// avoid overriding key with empty value.
-					continue
-				}
+			handlerResponse.Headers = headers
+			handlerResponse.Body = envelope.Body
+			handlerResponse.IsBase64Encoded = envelope.IsBase64Encoded
+			handlerResponse.raw = false
 
-				handlerResponse.Headers[key] = val
+			if envelope.StatusCode != 0 {
+				handlerResponse.StatusCode = envelope.StatusCode
 			}
+		case !utf8.Valid(bodyBytes):
+			// Bodies that are not valid UTF-8 can not be carried in a JSON response, they are base64 encoded.
+			handlerResponse.Body = BinaryBody(bodyBytes)
+			handlerResponse.IsBase64Encoded = true
+			handlerResponse.raw = false
 		}
 	}
 
-	if response.StatusCode == 0 {
+	if handlerResponse.StatusCode == 0 {
 		handlerResponse.StatusCode = http.StatusOK
 	}
 
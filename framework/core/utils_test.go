@@ -89,7 +89,7 @@ func TestGetResponseAllFields(t *testing.T) {
 func TestGetResponseB64Encoded(t *testing.T) {
 	t.Parallel()
 
-	bodyContent := strings.NewReader(`{"isBase64Encoded": true, "body": "body_test"}`)
+	bodyContent := strings.NewReader(`{"isBase64Encoded": true, "body": "Ym9keV90ZXN0"}`)
 	bodyContentCloser := io.NopCloser(bodyContent)
 
 	httpResp := http.Response{
@@ -97,15 +97,18 @@ func TestGetResponseB64Encoded(t *testing.T) {
 		StatusCode: http.StatusFound,
 	}
 
-	httpResp.Header = make(http.Header, 1)
+	httpResp.Header = make(http.Header, 2)
 	httpResp.Header.Set("Key", "value")
+	httpResp.Header.Set(HeaderResponseEnvelope, "true")
 
 	resp, err := GetResponse(&httpResp)
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, http.StatusFound, resp.StatusCode)
-	// Using assert.Contains instead of Equal because as b64 encoded is enabled, clean unmarshall is not performed here.
-	assert.Contains(t, string(resp.Body), "body_test")
+
+	decoded, err := resp.DecodedBody()
+	assert.NoError(t, err)
+	assert.Equal(t, "body_test", string(decoded))
 	assert.Equal(t, "value", resp.Headers["Key"][0])
 	assert.True(t, resp.IsBase64Encoded)
 }
@@ -122,8 +125,9 @@ func TestGetResponseJsonInJsonBody(t *testing.T) {
 		StatusCode: http.StatusFound,
 	}
 
-	httpResp.Header = make(http.Header, 1)
+	httpResp.Header = make(http.Header, 2)
 	httpResp.Header.Set("Key", "value")
+	httpResp.Header.Set(HeaderResponseEnvelope, "true")
 
 	resp, err := GetResponse(&httpResp)
 	assert.NoError(t, err)
@@ -221,6 +225,7 @@ func TestGetResponsFullJson(t *testing.T) {
 	httpResp := http.Response{
 		Body:       bodyContentCloser,
 		StatusCode: http.StatusFound,
+		Header:     http.Header{HeaderResponseEnvelope: {"true"}},
 	}
 
 	resp, err := GetResponse(&httpResp)
@@ -229,6 +234,7 @@ func TestGetResponsFullJson(t *testing.T) {
 
 	assert.Equal(t, http.StatusFound, resp.StatusCode)
 	assert.Equal(t, `"bodyTest"`, string(resp.Body))
+	assert.NotContains(t, resp.Headers, HeaderResponseEnvelope)
 	assert.False(t, resp.IsBase64Encoded)
 	assert.Equal(t, []string{"flatval"}, resp.Headers["flat"])
 	assert.Equal(t, []string{"val1", "val2"}, resp.Headers["array"])
@@ -264,3 +270,49 @@ func TestGetResponseNil(t *testing.T) {
 	assert.ErrorIs(t, err, ErrRespEmpty)
 	assert.Nil(t, resp)
 }
+
+func TestGetResponseEnvelopeOptIn(t *testing.T) {
+	t.Parallel()
+
+	const businessData = `{"statusCode": 5, "body": "not an envelope"}`
+
+	httpResp := http.Response{
+		Body:       io.NopCloser(strings.NewReader(businessData)),
+		StatusCode: http.StatusOK,
+	}
+
+	resp, err := GetResponse(&httpResp)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := resp.DecodedBody()
+	assert.NoError(t, err)
+	assert.Equal(t, businessData, string(body))
+
+	httpResp = http.Response{
+		Body:       io.NopCloser(strings.NewReader(`"quoted"`)),
+		StatusCode: http.StatusOK,
+	}
+
+	resp, err = GetResponse(&httpResp)
+	assert.NoError(t, err)
+
+	body, err = resp.DecodedBody()
+	assert.NoError(t, err)
+	assert.Equal(t, `"quoted"`, string(body))
+}
+
+func TestGetResponseEnvelopeInvalid(t *testing.T) {
+	t.Parallel()
+
+	httpResp := http.Response{
+		Body:       io.NopCloser(strings.NewReader(`{"statusCode": 5, "body": "invalid status"}`)),
+		StatusCode: http.StatusOK,
+		Header:     http.Header{HeaderResponseEnvelope: {"true"}},
+	}
+
+	resp, err := GetResponse(&httpResp)
+	assert.ErrorIs(t, err, ErrInvalidResponseEnvelope)
+	assert.ErrorContains(t, err, "statusCode 5 is not a valid HTTP status code")
+	assert.Nil(t, resp)
+}
@@ -0,0 +1,152 @@
+package function
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+const contentTypeJSON = "application/json"
+
+// StatusCoder can be implemented by errors returned by a JSON handler to choose the status code of the response.
+// Other errors result in a 500 Internal Server Error.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HTTPError is an error carrying the status code returned to the caller of a JSON handler.
+type HTTPError struct {
+	Code    int
+	Message string
+}
+
+// NewHTTPError creates an error returned with the given status code by a JSON handler.
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{Code: code, Message: message}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// StatusCode implements the StatusCoder interface.
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
+
+// errorBody is the JSON body returned by a JSON handler when an error occurs.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// JSON adapts a function working on typed values to a ScwFuncV1 handler. The request body is decoded as JSON into
+// Req and the returned Resp is encoded as JSON with the Content-Type header set. An empty body leaves Req to its zero
+// value. Decoding errors are returned as 400 Bad Request, errors returned by fn use the status code and message of
+// their StatusCoder implementation if any. Other errors are logged with slog and return a 500 Internal Server Error
+// without their message, so internal details do not leak to callers.
+func JSON[Req, Resp any](fn func(context.Context, Req) (Resp, error)) ScwFuncV1 {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: err.Error()})
+
+			return
+		}
+
+		statusCode, value := callJSON(r.Context(), fn, body)
+		writeJSON(w, statusCode, value)
+	}
+}
+
+// JSONV2 is the ScwFuncV2 variant of JSON, base64 encoded bodies of events are decoded.
+func JSONV2[Req, Resp any](fn func(context.Context, Req) (Resp, error)) ScwFuncV2 {
+	return func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		body, err := eventBody(event)
+		if err != nil {
+			return jsonResponse(http.StatusBadRequest, errorBody{Error: err.Error()})
+		}
+
+		return jsonResponse(callJSON(ctx, fn, body))
+	}
+}
+
+// callJSON decodes body into the request of fn, calls it and returns the status code and the value to encode in
+// the response body.
+func callJSON[Req, Resp any](ctx context.Context, fn func(context.Context, Req) (Resp, error), body []byte) (int, any) {
+	var req Req
+
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return http.StatusBadRequest, errorBody{Error: err.Error()}
+		}
+	}
+
+	resp, err := fn(ctx, req)
+	if err == nil {
+		return http.StatusOK, resp
+	}
+
+	var statusCoder StatusCoder
+	if errors.As(err, &statusCoder) {
+		return statusCoder.StatusCode(), errorBody{Error: err.Error()}
+	}
+
+	slog.ErrorContext(ctx, "JSON handler returned an error", "error", err)
+
+	return http.StatusInternalServerError, errorBody{Error: http.StatusText(http.StatusInternalServerError)}
+}
+
+// eventBody returns the body of the event, decoded if it is base64 encoded.
+//
+//nolint:gocritic
+func eventBody(event core.APIGatewayProxyRequest) ([]byte, error) {
+	if event.IsBase64Encoded {
+		return base64.StdEncoding.DecodeString(event.Body)
+	}
+
+	return []byte(event.Body), nil
+}
+
+// writeJSON writes value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	bodyBytes, err := json.Marshal(value)
+	if err != nil {
+		slog.Error("JSON handler response could not be encoded", "error", err)
+
+		statusCode = http.StatusInternalServerError
+		// marshalling errorBody never fails
+		bodyBytes, _ = json.Marshal(errorBody{Error: http.StatusText(statusCode)})
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(statusCode)
+
+	_, _ = w.Write(bodyBytes)
+}
+
+// jsonResponse encodes value as the JSON body of a response.
+func jsonResponse(statusCode int, value any) (core.ResponseHTTP, error) {
+	bodyBytes, err := json.Marshal(value)
+	if err != nil {
+		return core.ResponseHTTP{}, err
+	}
+
+	// body is wrapped in a JSON string so it is written as is, whatever the type of value
+	body, err := json.Marshal(string(bodyBytes))
+	if err != nil {
+		return core.ResponseHTTP{}, err
+	}
+
+	return core.ResponseHTTP{
+		StatusCode: statusCode,
+		Body:       body,
+		Headers:    map[string][]string{"Content-Type": {contentTypeJSON}},
+	}, nil
+}
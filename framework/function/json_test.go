@@ -0,0 +1,163 @@
+package function
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetRequest struct {
+	Name string `json:"name"`
+}
+
+type greetResponse struct {
+	Message string `json:"message"`
+}
+
+func greet(ctx context.Context, req greetRequest) (greetResponse, error) {
+	if req.Name == "" {
+		return greetResponse{}, fmt.Errorf("validation: %w", NewHTTPError(http.StatusUnprocessableEntity, "name is required"))
+	}
+
+	if req.Name == "fail" {
+		return greetResponse{}, errors.New("database password is hunter2")
+	}
+
+	return greetResponse{Message: "hello " + req.Name}, nil
+}
+
+func decodeBody(t *testing.T, resp core.ResponseHTTP) string {
+	t.Helper()
+
+	var body string
+	require.NoError(t, json.Unmarshal(resp.Body, &body))
+
+	return body
+}
+
+func serveJSON(handler ScwFuncV1, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	return recorder
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	recorder := serveJSON(JSON(greet), `{"name":"scaleway"}`)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"message":"hello scaleway"}`, recorder.Body.String())
+}
+
+func TestJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := JSON(greet)
+
+	testCases := []struct {
+		body       string
+		statusCode int
+		message    string
+	}{
+		{body: `not json`, statusCode: http.StatusBadRequest},
+		{body: ``, statusCode: http.StatusUnprocessableEntity, message: "validation: name is required"},
+		{body: `{"name":"fail"}`, statusCode: http.StatusInternalServerError, message: "Internal Server Error"},
+	}
+
+	for _, testCase := range testCases {
+		recorder := serveJSON(handler, testCase.body)
+
+		assert.Equal(t, testCase.statusCode, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+		var body errorBody
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		assert.NotEmpty(t, body.Error)
+
+		if testCase.message != "" {
+			assert.Equal(t, testCase.message, body.Error)
+		}
+	}
+}
+
+func TestJSONStringResponse(t *testing.T) {
+	t.Parallel()
+
+	handler := JSON(func(ctx context.Context, req struct{}) (string, error) {
+		return "plain", nil
+	})
+
+	assert.Equal(t, `"plain"`, serveJSON(handler, "").Body.String())
+}
+
+func TestJSONV2(t *testing.T) {
+	t.Parallel()
+
+	handler := JSONV2(greet)
+
+	resp, err := handler(context.Background(), core.APIGatewayProxyRequest{Body: `{"name":"scaleway"}`})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"application/json"}, resp.Headers["Content-Type"])
+	assert.JSONEq(t, `{"message":"hello scaleway"}`, decodeBody(t, resp))
+}
+
+func TestJSONV2Base64(t *testing.T) {
+	t.Parallel()
+
+	handler := JSONV2(greet)
+
+	resp, err := handler(context.Background(), core.APIGatewayProxyRequest{
+		Body:            base64.StdEncoding.EncodeToString([]byte(`{"name":"base64"}`)),
+		IsBase64Encoded: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"message":"hello base64"}`, decodeBody(t, resp))
+}
+
+func TestJSONV2Errors(t *testing.T) {
+	t.Parallel()
+
+	handler := JSONV2(greet)
+
+	testCases := []struct {
+		event      core.APIGatewayProxyRequest
+		statusCode int
+		message    string
+	}{
+		{event: core.APIGatewayProxyRequest{Body: "not base64", IsBase64Encoded: true}, statusCode: http.StatusBadRequest},
+		{event: core.APIGatewayProxyRequest{Body: `{"name":"fail"}`}, statusCode: http.StatusInternalServerError,
+			message: "Internal Server Error"},
+	}
+
+	for _, testCase := range testCases {
+		resp, err := handler(context.Background(), testCase.event)
+		require.NoError(t, err)
+
+		assert.Equal(t, testCase.statusCode, resp.StatusCode)
+
+		var body errorBody
+		require.NoError(t, json.Unmarshal([]byte(decodeBody(t, resp)), &body))
+		assert.NotEmpty(t, body.Error)
+
+		if testCase.message != "" {
+			assert.Equal(t, testCase.message, body.Error)
+		}
+	}
+}
@@ -1,8 +1,113 @@
 package function
 
-import "net/http"
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+// ErrUnsupportedHandler is returned when a handler is nil.
+var ErrUnsupportedHandler = errors.New("handler is nil")
+
+// Handler is the constraint satisfied by the versioned function handler prototypes, ScwFuncV1 and ScwFuncV2, and by
+// function types with the same signature such as http.HandlerFunc. Entry points accepting a Handler detect its
+// version, use AsV1 and AsV2 to detect it.
+type Handler interface {
+	~func(http.ResponseWriter, *http.Request) |
+		~func(context.Context, core.APIGatewayProxyRequest) (core.ResponseHTTP, error)
+}
 
 // ScwFuncV1 is the prototype of a function handler that support std http objects.
 // Version is embedded in the type to allow evolutions, this can allow core runtime to
 // dynamically check for type to set appropriate behavior.
 type ScwFuncV1 func(http.ResponseWriter, *http.Request)
+
+// ScwFuncV2 is the prototype of a function handler working on the event sent by the platform.
+// The context carries the execution context and the request ID, use core.ExecutionContextFrom and
+// core.RequestIDFrom to read them. A returned error makes the invocation fail with a 500 error.
+//
+// The local server runs ScwFuncV2 handlers directly, but the Go runtime of the platform only calls ScwFuncV1
+// handlers: wrap the handler with V1 in the code you deploy.
+type ScwFuncV2 func(context.Context, core.APIGatewayProxyRequest) (core.ResponseHTTP, error)
+
+// V1 adapts a ScwFuncV2 handler to the ScwFuncV1 prototype called by the Go runtime of the platform. The request is
+// converted to an event as the local server does and the returned response is written with its status code,
+// headers and decoded body. Errors returned by the handler are logged with slog and return a 500 Internal Server
+// Error.
+func V1(handler ScwFuncV2) ScwFuncV1 {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		formatter := core.EventFormatter{BinaryContentTypes: core.DefaultBinaryContentTypes}
+
+		resp, err := handler(r.Context(), formatter.Format(r, body))
+		if err != nil {
+			slog.ErrorContext(r.Context(), "handler returned an error", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+
+		responseBody, err := resp.DecodedBody()
+		if err != nil {
+			slog.ErrorContext(r.Context(), "handler returned an invalid response", "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+
+		if resp.StatusCode == 0 {
+			resp.StatusCode = http.StatusOK
+		}
+
+		core.SetHeaders(resp.Headers, w.Header())
+		w.WriteHeader(resp.StatusCode)
+
+		_, _ = w.Write(responseBody)
+	}
+}
+
+// AsV1 returns the handler as a ScwFuncV1 if it matches this prototype and is not nil.
+func AsV1[H Handler](handler H) (ScwFuncV1, bool) {
+	return convertHandler[ScwFuncV1](handler)
+}
+
+// AsV2 returns the handler as a ScwFuncV2 if it matches this prototype and is not nil.
+func AsV2[H Handler](handler H) (ScwFuncV2, bool) {
+	return convertHandler[ScwFuncV2](handler)
+}
+
+// Validate returns ErrUnsupportedHandler if the handler is nil.
+func Validate[H Handler](handler H) error {
+	if reflect.ValueOf(handler).IsNil() {
+		return ErrUnsupportedHandler
+	}
+
+	return nil
+}
+
+// convertHandler converts the handler to the prototype T if their signatures match, whatever the name of its type.
+func convertHandler[T any, H Handler](handler H) (T, bool) {
+	var converted T
+
+	value := reflect.ValueOf(handler)
+	targetType := reflect.TypeOf(converted)
+
+	if value.IsNil() || !value.Type().ConvertibleTo(targetType) {
+		return converted, false
+	}
+
+	converted, ok := value.Convert(targetType).Interface().(T)
+
+	return converted, ok
+}
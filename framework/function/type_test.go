@@ -0,0 +1,101 @@
+package function
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// namedHandler has the signature of ScwFuncV1 under another name.
+type namedHandler func(http.ResponseWriter, *http.Request)
+
+func assertV1[H Handler](t *testing.T, handler H) {
+	t.Helper()
+
+	_, ok := AsV1(handler)
+	assert.True(t, ok)
+
+	_, ok = AsV2(handler)
+	assert.False(t, ok)
+
+	assert.NoError(t, Validate(handler))
+}
+
+func assertV2[H Handler](t *testing.T, handler H) {
+	t.Helper()
+
+	_, ok := AsV2(handler)
+	assert.True(t, ok)
+
+	_, ok = AsV1(handler)
+	assert.False(t, ok)
+
+	assert.NoError(t, Validate(handler))
+}
+
+func TestHandlerVersion(t *testing.T) {
+	t.Parallel()
+
+	handlerV1 := func(w http.ResponseWriter, r *http.Request) {}
+	handlerV2 := func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		return core.ResponseHTTP{}, nil
+	}
+
+	assertV1(t, handlerV1)
+	assertV1(t, ScwFuncV1(handlerV1))
+	assertV1(t, http.HandlerFunc(handlerV1))
+	assertV1(t, namedHandler(handlerV1))
+
+	assertV2(t, handlerV2)
+	assertV2(t, ScwFuncV2(handlerV2))
+}
+
+func TestHandlerNil(t *testing.T) {
+	t.Parallel()
+
+	var nilV1 ScwFuncV1
+
+	var nilV2 ScwFuncV2
+
+	assert.ErrorIs(t, Validate(nilV1), ErrUnsupportedHandler)
+	assert.ErrorIs(t, Validate(nilV2), ErrUnsupportedHandler)
+
+	_, ok := AsV1(nilV1)
+	assert.False(t, ok)
+
+	_, ok = AsV2(nilV2)
+	assert.False(t, ok)
+}
+
+func TestV1(t *testing.T) {
+	t.Parallel()
+
+	handler := V1(func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		if event.Path == "/fail" {
+			return core.ResponseHTTP{}, errors.New("failure")
+		}
+
+		return core.BinaryResponse(http.StatusCreated, "image/png", []byte(event.HTTPMethod+" "+event.Body)), nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "POST payload", recorder.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/fail", http.NoBody)
+	recorder = httptest.NewRecorder()
+	handler(recorder, req)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "failure")
+}
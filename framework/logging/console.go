@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ANSI escape codes used to color console output.
+const (
+	colorReset  = "\033[0m"
+	colorGray   = "\033[90m"
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+// ConsoleHandler is a slog.Handler writing human-friendly lines, it is meant for local runs and not for production.
+//
+//	15:04:05.000 INFO  request processed status=200
+type ConsoleHandler struct {
+	opts   slog.HandlerOptions
+	color  bool
+	attrs  string
+	prefix string
+
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+// NewConsoleHandler creates a ConsoleHandler writing to w, levels and attribute keys are colored if color is true.
+func NewConsoleHandler(w io.Writer, color bool, opts *slog.HandlerOptions) *ConsoleHandler {
+	handler := &ConsoleHandler{
+		color: color,
+		mu:    &sync.Mutex{},
+		w:     w,
+	}
+
+	if opts != nil {
+		handler.opts = *opts
+	}
+
+	return handler
+}
+
+// Enabled implements slog.Handler.
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic
+func (h *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	builder := strings.Builder{}
+
+	if !record.Time.IsZero() {
+		builder.WriteString(h.colorize(colorGray, record.Time.Format(time.TimeOnly+".000")))
+		builder.WriteByte(' ')
+	}
+
+	builder.WriteString(h.colorize(levelColor(record.Level), fmt.Sprintf("%-5s", record.Level.String())))
+	builder.WriteByte(' ')
+	builder.WriteString(record.Message)
+	builder.WriteString(h.attrs)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		h.appendAttr(&builder, h.prefix, attr)
+
+		return true
+	})
+
+	builder.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err := io.WriteString(h.w, builder.String())
+
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	builder := strings.Builder{}
+	builder.WriteString(h.attrs)
+
+	for _, attr := range attrs {
+		h.appendAttr(&builder, h.prefix, attr)
+	}
+
+	clone := *h
+	clone.attrs = builder.String()
+
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+
+	return &clone
+}
+
+// appendAttr writes attr as " key=value", groups are flattened with dotted keys.
+func (h *ConsoleHandler) appendAttr(builder *strings.Builder, prefix string, attr slog.Attr) {
+	if h.opts.ReplaceAttr != nil && attr.Value.Kind() != slog.KindGroup {
+		attr = h.opts.ReplaceAttr(groups(prefix), attr)
+	}
+
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+
+		for _, groupAttr := range attr.Value.Group() {
+			h.appendAttr(builder, groupPrefix, groupAttr)
+		}
+
+		return
+	}
+
+	value := attr.Value.String()
+
+	switch {
+	case strings.Contains(value, "\n"):
+		// multi-line values like stack traces are kept readable on their own lines
+		value = "\n" + strings.TrimSuffix(value, "\n")
+	case strings.ContainsAny(value, " \t\"="):
+		value = strconv.Quote(value)
+	}
+
+	builder.WriteByte(' ')
+	builder.WriteString(h.colorize(colorCyan, prefix+attr.Key+"="))
+	builder.WriteString(value)
+}
+
+// groups returns the names of the groups of a dotted prefix.
+func groups(prefix string) []string {
+	if prefix == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(prefix, "."), ".")
+}
+
+// colorize wraps text with the given color when colors are enabled.
+func (h *ConsoleHandler) colorize(color, text string) string {
+	if !h.color {
+		return text
+	}
+
+	return color + text + colorReset
+}
+
+// levelColor returns the color used to display a level.
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
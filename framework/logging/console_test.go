@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewConsoleHandler(buf, false, nil))
+
+	logger.With("fixed", "attr").WithGroup("req").Info("request processed", "status", 200, "path", "/a b")
+	logger.Debug("hidden")
+
+	assert.Regexp(t, `^\d{2}:\d{2}:\d{2}\.\d{3} INFO  request processed fixed=attr req\.status=200 req\.path="/a b"\n$`, buf.String())
+}
+
+func TestConsoleHandlerColorAndLevel(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewConsoleHandler(buf, true, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.Debug("debug message", "stack", "line1\nline2\n")
+
+	assert.Contains(t, buf.String(), colorGray+"DEBUG"+colorReset)
+	assert.Contains(t, buf.String(), "stack="+colorReset+"\nline1\nline2\n")
+}
+
+func TestLevelFromEnv(t *testing.T) {
+	for value, expected := range map[string]slog.Level{
+		"":        slog.LevelInfo,
+		"debug":   slog.LevelDebug,
+		"WARN":    slog.LevelWarn,
+		"error":   slog.LevelError,
+		"invalid": slog.LevelInfo,
+	} {
+		t.Setenv(EnvLogLevel, value)
+		assert.Equal(t, expected, LevelFromEnv())
+	}
+}
@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+// Keys of the attributes added to log records.
+const (
+	KeyMessage         = "message"
+	KeyRequestID       = "request_id"
+	KeyFunctionName    = "function_name"
+	KeyFunctionVersion = "function_version"
+)
+
+// Handler is a slog.Handler attaching the request ID and the function name and version to every record.
+// Values are read from the context given to the logger, see core.ExecutionContextFrom and core.RequestIDFrom,
+// unless the handler was scoped to a request with FromRequest or FromContext.
+type Handler struct {
+	inner  slog.Handler
+	scoped bool
+}
+
+// NewHandler creates a Handler writing JSON lines in the format ingested by Scaleway Cockpit: time, lowercase level
+// and message keys followed by the attributes of the record.
+func NewHandler(w io.Writer, opts *slog.HandlerOptions) *Handler {
+	cockpitOpts := slog.HandlerOptions{}
+	if opts != nil {
+		cockpitOpts = *opts
+	}
+
+	replaceAttr := cockpitOpts.ReplaceAttr
+	cockpitOpts.ReplaceAttr = func(groups []string, attr slog.Attr) slog.Attr {
+		if len(groups) == 0 {
+			attr = cockpitAttr(attr)
+		}
+
+		if replaceAttr != nil {
+			return replaceAttr(groups, attr)
+		}
+
+		return attr
+	}
+
+	return Wrap(slog.NewJSONHandler(w, &cockpitOpts))
+}
+
+// Wrap returns a Handler attaching request and function attributes to the records passed to inner.
+func Wrap(inner slog.Handler) *Handler {
+	if handler, ok := inner.(*Handler); ok {
+		return handler
+	}
+
+	return &Handler{inner: inner}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if !h.scoped && ctx != nil {
+		record.AddAttrs(requestAttrs(core.RequestIDFrom(ctx), core.ExecutionContextFrom(ctx))...)
+	}
+
+	return h.inner.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), scoped: h.scoped}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name), scoped: h.scoped}
+}
+
+// scope returns a handler with request attributes already attached, they are not read from context anymore.
+func (h *Handler) scope(attrs []slog.Attr) *Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs), scoped: true}
+}
+
+// requestAttrs returns the attributes identifying a request and the function processing it.
+func requestAttrs(requestID string, execCtx core.ExecutionContext) []slog.Attr {
+	attrs := make([]slog.Attr, 0, 3)
+
+	if requestID != "" {
+		attrs = append(attrs, slog.String(KeyRequestID, requestID))
+	}
+
+	return append(attrs,
+		slog.String(KeyFunctionName, execCtx.FunctionName),
+		slog.String(KeyFunctionVersion, execCtx.FunctionVersion),
+	)
+}
+
+// cockpitAttr renames and formats built-in attributes to the format ingested by Scaleway Cockpit.
+func cockpitAttr(attr slog.Attr) slog.Attr {
+	switch attr.Key {
+	case slog.MessageKey:
+		attr.Key = KeyMessage
+	case slog.LevelKey:
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			attr.Value = slog.StringValue(strings.ToLower(level.String()))
+		}
+	}
+
+	return attr
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		line := map[string]any{}
+		require.NoError(t, decoder.Decode(&line))
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func TestHandlerCockpitFormat(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewHandler(buf, nil))
+
+	logger.Warn("something happened", "key", "value")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+
+	assert.Equal(t, "something happened", lines[0][KeyMessage])
+	assert.Equal(t, "warn", lines[0]["level"])
+	assert.Equal(t, "value", lines[0]["key"])
+	assert.NotEmpty(t, lines[0]["time"])
+	assert.Equal(t, core.GetExecutionContext().FunctionName, lines[0][KeyFunctionName])
+	assert.NotContains(t, lines[0], KeyRequestID)
+}
+
+func TestHandlerContext(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ctx := core.WithRequestID(context.Background(), "request-id")
+	ctx = core.WithExecutionContext(ctx, core.ExecutionContext{FunctionName: "my-function", FunctionVersion: "1.0.0"})
+
+	logger.With("attr", 1).DebugContext(ctx, "debug message")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+
+	assert.Equal(t, "debug", lines[0]["level"])
+	assert.Equal(t, "request-id", lines[0][KeyRequestID])
+	assert.Equal(t, "my-function", lines[0][KeyFunctionName])
+	assert.Equal(t, "1.0.0", lines[0][KeyFunctionVersion])
+	assert.EqualValues(t, 1, lines[0]["attr"])
+}
+
+func TestScopedLogger(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	base := slog.New(NewHandler(buf, nil))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("X-Request-Id", "header-request-id")
+
+	logger := scopedLogger(base, core.RequestIDFromHTTP(req), core.ExecutionContextFrom(req.Context()))
+
+	// context values are ignored once the logger is scoped to a request, attributes are not duplicated
+	logger.InfoContext(core.WithRequestID(context.Background(), "other"), "scoped message")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+
+	assert.Equal(t, "header-request-id", lines[0][KeyRequestID])
+	assert.Equal(t, "info", lines[0]["level"])
+}
+
+func TestScopedLoggerForeignHandler(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	base := slog.New(slog.NewJSONHandler(buf, nil))
+
+	logger := scopedLogger(base, "request-id", core.ExecutionContext{FunctionName: "my-function"})
+	logger.Info("foreign handler")
+
+	lines := decodeLines(t, buf)
+	require.Len(t, lines, 1)
+
+	assert.Equal(t, "request-id", lines[0][KeyRequestID])
+	assert.Equal(t, "my-function", lines[0][KeyFunctionName])
+	assert.Equal(t, "foreign handler", lines[0][slog.MessageKey])
+}
@@ -1 +1,88 @@
 package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+// EnvLogLevel is the environment variable used to set the minimum level of logs: debug, info, warn or error.
+const EnvLogLevel = "SCW_LOG_LEVEL"
+
+var defaultLogger atomic.Pointer[slog.Logger]
+
+// LevelFromEnv returns the level set by EnvLogLevel, or info if it is not set or invalid.
+func LevelFromEnv() slog.Level {
+	level := slog.LevelInfo
+
+	if value := os.Getenv(EnvLogLevel); value != "" {
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return slog.LevelInfo
+		}
+	}
+
+	return level
+}
+
+// New creates a logger writing JSON lines in the format ingested by Scaleway Cockpit, see NewHandler.
+// If opts does not set a level, the level is read from EnvLogLevel.
+func New(opts *slog.HandlerOptions) *slog.Logger {
+	levelOpts := slog.HandlerOptions{}
+	if opts != nil {
+		levelOpts = *opts
+	}
+
+	if levelOpts.Level == nil {
+		levelOpts.Level = LevelFromEnv()
+	}
+
+	return slog.New(NewHandler(os.Stdout, &levelOpts))
+}
+
+// Default returns the logger used by FromRequest and FromContext, by default it writes to stdout with New.
+func Default() *slog.Logger {
+	if logger := defaultLogger.Load(); logger != nil {
+		return logger
+	}
+
+	return New(nil)
+}
+
+// SetDefault replaces the logger used by FromRequest and FromContext.
+func SetDefault(logger *slog.Logger) {
+	defaultLogger.Store(logger)
+}
+
+// FromRequest returns the default logger with the request ID and the function name and version attached.
+// Use it from a ScwFuncV1 handler:
+//
+//	logging.FromRequest(r).Info("processing order", "id", orderID)
+func FromRequest(req *http.Request) *slog.Logger {
+	return scopedLogger(Default(), core.RequestIDFromHTTP(req), core.ExecutionContextFrom(req.Context()))
+}
+
+// FromContext returns the default logger with the request ID and the function name and version carried by ctx
+// attached, it is the equivalent of FromRequest for ScwFuncV2 handlers.
+func FromContext(ctx context.Context) *slog.Logger {
+	return scopedLogger(Default(), core.RequestIDFrom(ctx), core.ExecutionContextFrom(ctx))
+}
+
+// scopedLogger attaches request attributes to logger.
+func scopedLogger(logger *slog.Logger, requestID string, execCtx core.ExecutionContext) *slog.Logger {
+	attrs := requestAttrs(requestID, execCtx)
+
+	if handler, ok := logger.Handler().(*Handler); ok {
+		return slog.New(handler.scope(attrs))
+	}
+
+	args := make([]any, len(attrs))
+	for idx := range attrs {
+		args[idx] = attrs[idx]
+	}
+
+	return logger.With(args...)
+}
@@ -0,0 +1,238 @@
+package local
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+const (
+	// keyPairBits is the size of the RSA keys generated to sign tokens.
+	keyPairBits = 2048
+
+	// tokenAlgorithm is the JWT algorithm of tokens, as used by the platform.
+	tokenAlgorithm = "RS256"
+
+	// tokenIssuer is the issuer of tokens minted locally.
+	tokenIssuer = "SCALEWAY"
+
+	// tokenParts is the number of dot separated parts of a token: header, payload and signature.
+	tokenParts = 3
+)
+
+var (
+	// ErrInvalidToken is returned when a token can not authenticate a request to a private function.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// errMissingKeyPair is returned when a private function is configured without key pair.
+	errMissingKeyPair = errors.New("private function requires a key pair")
+)
+
+// KeyPair signs and verifies the tokens of private functions served locally, like the keys of a namespace.
+type KeyPair struct {
+	privateKey *rsa.PrivateKey
+}
+
+// GenerateKeyPair generates a key pair to serve private functions and mint their tokens.
+func GenerateKeyPair() (*KeyPair, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyPairBits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{privateKey: privateKey}, nil
+}
+
+// TokenClaims defines the scope and the validity of a token.
+type TokenClaims struct {
+	// NamespaceName is the namespace the token gives access to, it must match the execution context of the function.
+	NamespaceName string
+
+	// FunctionName restricts the token to a function of the namespace, all functions are allowed when empty.
+	FunctionName string
+
+	// ExpiresAt is the expiration time of the token, the token does not expire when zero.
+	ExpiresAt time.Time
+}
+
+// applicationClaim is the scope of a token as encoded in its payload.
+type applicationClaim struct {
+	NamespaceID   string `json:"namespace_id"`
+	ApplicationID string `json:"application_id"`
+}
+
+// tokenPayload is the payload of tokens.
+type tokenPayload struct {
+	Issuer           string             `json:"iss"`
+	IssuedAt         int64              `json:"iat"`
+	ExpiresAt        int64              `json:"exp,omitempty"`
+	ApplicationClaim []applicationClaim `json:"application_claim"`
+}
+
+// tokenHeader is the header of tokens.
+type tokenHeader struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// Token mints a token to call private functions served with WithPrivateFunction, send it in the X-Auth-Token header.
+func (k *KeyPair) Token(claims TokenClaims) (string, error) {
+	payload := tokenPayload{
+		Issuer:   tokenIssuer,
+		IssuedAt: time.Now().Unix(),
+		ApplicationClaim: []applicationClaim{{
+			NamespaceID:   claims.NamespaceName,
+			ApplicationID: claims.FunctionName,
+		}},
+	}
+
+	if !claims.ExpiresAt.IsZero() {
+		payload.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+
+	headerJSON, err := json.Marshal(tokenHeader{Algorithm: tokenAlgorithm, Type: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(payloadJSON)
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, k.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// FunctionToken mints a token valid for ttl giving access to the function of the execution context, see Token.
+func (k *KeyPair) FunctionToken(execCtx core.ExecutionContext, ttl time.Duration) (string, error) {
+	return k.Token(TokenClaims{
+		NamespaceName: execCtx.NamespaceName,
+		FunctionName:  execCtx.FunctionName,
+		ExpiresAt:     time.Now().Add(ttl),
+	})
+}
+
+// verify checks the signature and the validity of the token and that it gives access to the function.
+func (k *KeyPair) verify(token string, execCtx core.ExecutionContext, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != tokenParts {
+		return fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	header := tokenHeader{}
+	if err := decodeTokenPart(parts[0], &header); err != nil {
+		return err
+	}
+
+	if header.Algorithm != tokenAlgorithm {
+		return fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, header.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&k.privateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("%w: signature does not match the key pair of the function", ErrInvalidToken)
+	}
+
+	payload := tokenPayload{}
+	if err := decodeTokenPart(parts[1], &payload); err != nil {
+		return err
+	}
+
+	if payload.ExpiresAt != 0 && now.Unix() >= payload.ExpiresAt {
+		return fmt.Errorf("%w: token expired at %s", ErrInvalidToken, time.Unix(payload.ExpiresAt, 0).UTC())
+	}
+
+	for _, claim := range payload.ApplicationClaim {
+		if claim.NamespaceID == execCtx.NamespaceName &&
+			(claim.ApplicationID == "" || claim.ApplicationID == execCtx.FunctionName) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: token does not give access to function %q of namespace %q", ErrInvalidToken,
+		execCtx.FunctionName, execCtx.NamespaceName)
+}
+
+// decodeTokenPart decodes a base64 JSON part of a token into target.
+func decodeTokenPart(part string, target any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	if err := json.Unmarshal(decoded, target); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// WithPrivateFunction serves the function as private: requests must carry a token minted with keyPair in the
+// X-Auth-Token header, see KeyPair.Token. As the platform does, requests without token get a 401 error and
+// requests with an invalid, expired or out of scope token get a 403 error.
+func WithPrivateFunction(keyPair *KeyPair) Option {
+	return func(s *Server) {
+		if keyPair == nil {
+			s.err = errors.Join(s.err, errMissingKeyPair)
+
+			return
+		}
+
+		s.private = true
+		s.keyPair = keyPair
+	}
+}
+
+// authorize checks the token of requests to private functions, it writes the error response and returns false when
+// the request is rejected. Triggers invoke private functions from within the platform, their requests have no token.
+func (s *Server) authorize(httpResp http.ResponseWriter, httpReq *http.Request) bool {
+	if !s.private || isTriggerRequest(httpReq) {
+		return true
+	}
+
+	token := httpReq.Header.Get(authTokenHeader)
+	if token == "" {
+		s.logger.Warn("request rejected because the function is private and no token was given")
+		writeInfraError(httpResp, http.StatusUnauthorized, missingTokenMessage)
+
+		return false
+	}
+
+	if s.keyPair == nil {
+		s.logger.Debug("token not verified, use WithPrivateFunction to verify tokens of private functions")
+
+		return true
+	}
+
+	if err := s.keyPair.verify(token, s.executionContext, time.Now()); err != nil {
+		s.logger.Warn("request rejected because its token is invalid", "error", err)
+		writeInfraError(httpResp, http.StatusForbidden, invalidTokenMessage)
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,104 @@
+package local_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPrivateFunction(t *testing.T) {
+	t.Parallel()
+
+	keyPair, err := local.GenerateKeyPair()
+	require.NoError(t, err)
+
+	otherKeyPair, err := local.GenerateKeyPair()
+	require.NoError(t, err)
+
+	execCtx := core.ExecutionContext{FunctionName: "orders", NamespaceName: "shop"}
+
+	token := func(t *testing.T, keyPair *local.KeyPair, claims local.TokenClaims) string {
+		t.Helper()
+
+		signed, err := keyPair.Token(claims)
+		require.NoError(t, err)
+
+		return signed
+	}
+
+	functionToken, err := keyPair.FunctionToken(execCtx, time.Minute)
+	require.NoError(t, err)
+
+	validToken := token(t, keyPair, local.TokenClaims{NamespaceName: "shop", FunctionName: "orders"})
+	tests := []struct {
+		name     string
+		token    string
+		expected int
+	}{
+		{name: "function token", token: functionToken, expected: http.StatusOK},
+		{name: "namespace token", token: token(t, keyPair, local.TokenClaims{NamespaceName: "shop"}), expected: http.StatusOK},
+		{name: "missing token", expected: http.StatusUnauthorized},
+		{name: "malformed token", token: "not.a.token", expected: http.StatusForbidden},
+		{name: "tampered token", token: validToken[:len(validToken)-4] + "AAAA", expected: http.StatusForbidden},
+		{
+			name:     "other key pair",
+			token:    token(t, otherKeyPair, local.TokenClaims{NamespaceName: "shop", FunctionName: "orders"}),
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "other function",
+			token:    token(t, keyPair, local.TokenClaims{NamespaceName: "shop", FunctionName: "users"}),
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "other namespace",
+			token:    token(t, keyPair, local.TokenClaims{NamespaceName: "blog"}),
+			expected: http.StatusForbidden,
+		},
+		{
+			name: "expired token",
+			token: token(t, keyPair, local.TokenClaims{
+				NamespaceName: "shop",
+				ExpiresAt:     time.Now().Add(-time.Minute),
+			}),
+			expected: http.StatusForbidden,
+		},
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.token != "" {
+				req.Header.Set("X-Auth-Token", tt.token)
+			}
+
+			recorder := httptest.NewRecorder()
+
+			local.CoreProcessing(recorder, req, handler, local.WithLogger(discardLogger()),
+				local.WithExecutionContext(execCtx), local.WithPrivateFunction(keyPair))
+
+			assert.Equal(t, tt.expected, recorder.Code)
+		})
+	}
+}
+
+func TestWithPrivateFunctionMissingKeyPair(t *testing.T) {
+	t.Parallel()
+
+	_, err := local.Start(context.Background(), func(w http.ResponseWriter, r *http.Request) {},
+		local.WithPrivateFunction(nil))
+	assert.Error(t, err)
+}
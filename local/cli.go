@@ -0,0 +1,96 @@
+package local
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/scaleway/serverless-functions-go/framework/function"
+)
+
+// errCLIUsage is returned when the command line given to RunCLI is invalid.
+var errCLIUsage = errors.New("usage: [invoke --event event.json]")
+
+// RunCLI is the entry point of the main package of a function. Without arguments it serves the handler, see
+// ServeHandler. With "invoke --event event.json" it invokes the handler once with the event document without
+// starting a server, see InvokeEvent, and prints the status, headers and body of the response as CoreProcessing
+// produces them:
+//
+//	go run ./cmd invoke --event event.json
+//
+// Options are applied in both cases. The program exits with status 1 if the command fails.
+func RunCLI[H function.Handler](handler H, options ...Option) {
+	if len(os.Args) < 2 {
+		ServeHandler(handler, options...)
+
+		return
+	}
+
+	if err := runCLI(os.Args[1:], os.Stdout, handler, options...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runCLI executes the command given by args and writes its output to out.
+func runCLI[H function.Handler](args []string, out io.Writer, handler H, options ...Option) error {
+	if len(args) == 0 || args[0] != "invoke" {
+		return errCLIUsage
+	}
+
+	flags := flag.NewFlagSet("invoke", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	eventFile := flags.String("event", "", "path of the event JSON document")
+
+	if err := flags.Parse(args[1:]); err != nil || *eventFile == "" {
+		return errCLIUsage
+	}
+
+	eventJSON, err := os.ReadFile(*eventFile)
+	if err != nil {
+		return err
+	}
+
+	resp, err := InvokeEvent(handler, eventJSON, options...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return printResponse(out, resp)
+}
+
+// printResponse writes the status, headers sorted by name and body of the response.
+func printResponse(out io.Writer, resp *http.Response) error {
+	if _, err := fmt.Fprintf(out, "%s %s\n", resp.Proto, resp.Status); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(resp.Header))
+	for key := range resp.Header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range resp.Header[key] {
+			if _, err := fmt.Fprintf(out, "%s: %s\n", key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintln(out); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(out, resp.Body)
+
+	return err
+}
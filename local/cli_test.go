@@ -0,0 +1,51 @@
+package local
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCLIInvoke(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path+"?"+r.URL.RawQuery)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}
+
+	eventFile := filepath.Join(t.TempDir(), "event.json")
+	require.NoError(t, os.WriteFile(eventFile,
+		[]byte(`{"path": "/orders", "httpMethod": "POST", "queryStringParameters": {"id": "1"}, "body": "{}"}`), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	out := &bytes.Buffer{}
+	require.NoError(t, runCLI([]string{"invoke", "--event", eventFile}, out, handler, WithLogger(logger)))
+
+	assert.Contains(t, out.String(), "HTTP/1.1 201 Created\n")
+	assert.Contains(t, out.String(), "\nX-Path: /orders?id=1\n")
+	assert.Contains(t, out.String(), "\nServer: envoy\n")
+	assert.Contains(t, out.String(), "\n\ncreated")
+}
+
+func TestRunCLIUsage(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	for _, args := range [][]string{{"unknown"}, {"invoke"}, {"invoke", "--unknown"}} {
+		assert.ErrorIs(t, runCLI(args, &bytes.Buffer{}, handler), errCLIUsage)
+	}
+
+	err := runCLI([]string{"invoke", "--event", filepath.Join(t.TempDir(), "missing.json")}, &bytes.Buffer{}, handler)
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"gopkg.in/yaml.v3"
+)
+
+// privacyPrivate is the privacy of functions that can only be called with a token.
+const privacyPrivate = "private"
+
+// ErrInvalidConfig is returned when the configuration file can not be applied to the local server.
+var ErrInvalidConfig = errors.New("invalid configuration file")
+
+// configVariableRegexp matches the variables of the configuration file, like ${env:NAME}.
+var configVariableRegexp = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// serverlessConfig is the subset of the Serverless Framework configuration used by the Scaleway plugin that is
+// emulated by the local server.
+type serverlessConfig struct {
+	Service  string `yaml:"service"`
+	Provider struct {
+		Region string            `yaml:"scwRegion"`
+		Env    map[string]string `yaml:"env"`
+		Secret map[string]string `yaml:"secret"`
+	} `yaml:"provider"`
+	Functions map[string]functionConfig `yaml:"functions"`
+}
+
+// functionConfig is the entry of a function in the configuration file.
+type functionConfig struct {
+	MemoryLimit int               `yaml:"memoryLimit"`
+	Timeout     configDuration    `yaml:"timeout"`
+	Privacy     string            `yaml:"privacy"`
+	Env         map[string]string `yaml:"env"`
+	Secret      map[string]string `yaml:"secret"`
+	Events      []struct {
+		Schedule *struct {
+			Rate  string `yaml:"rate"`
+			Input any    `yaml:"input"`
+		} `yaml:"schedule"`
+	} `yaml:"events"`
+}
+
+// configDuration is a timeout of the configuration file, either a duration ("20s") or a number of seconds.
+type configDuration time.Duration
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *configDuration) UnmarshalYAML(value *yaml.Node) error {
+	if seconds, err := strconv.Atoi(value.Value); err == nil {
+		*d = configDuration(time.Duration(seconds) * time.Second)
+
+		return nil
+	}
+
+	duration, err := time.ParseDuration(value.Value)
+	if err != nil {
+		return fmt.Errorf("timeout %q: %w", value.Value, err)
+	}
+
+	*d = configDuration(duration)
+
+	return nil
+}
+
+// WithConfigFile applies the configuration of the function from a Serverless Framework file (serverless.yml) used
+// by the Scaleway plugin: environment variables and secrets, memory limit, timeout, CRON triggers and privacy. The
+// namespace and region of the execution context are read from the service and the provider.
+//
+// The file is applied once all other options are applied, whatever their order, and its values take precedence.
+// The function entry is the one named like the function name of the execution context, e.g. set with
+// WithExecutionContext or by WithFunction, or the only function of the file.
+//
+// In environment variables and secrets, ${env:NAME} variables are replaced by the value of NAME in the environment
+// of the process, as the Serverless Framework does on deployment. Other variables, like ${self:...}, are not
+// supported and make the option fail.
+func WithConfigFile(path string) Option {
+	return func(s *Server) {
+		s.configFiles = append(s.configFiles, path)
+	}
+}
+
+// applyConfigFiles applies the configuration files given with WithConfigFile to the server.
+func (s *Server) applyConfigFiles() {
+	for _, path := range s.configFiles {
+		if err := s.applyConfigFile(path); err != nil {
+			s.err = errors.Join(s.err, fmt.Errorf("%w %s: %w", ErrInvalidConfig, path, err))
+		}
+	}
+}
+
+// applyConfigFile reads the configuration file and applies the entry of the function to the server.
+func (s *Server) applyConfigFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	config := serverlessConfig{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return err
+	}
+
+	name, fnConfig, err := config.function(s.executionContext.FunctionName)
+	if err != nil {
+		return err
+	}
+
+	options := []Option{WithExecutionContext(core.ExecutionContext{
+		FunctionName:    name,
+		MemoryLimitInMB: fnConfig.MemoryLimit,
+		NamespaceName:   config.Service,
+		Region:          config.Provider.Region,
+	})}
+
+	if fnConfig.Timeout > 0 {
+		options = append(options, WithTimeout(time.Duration(fnConfig.Timeout)))
+	}
+
+	for _, event := range fnConfig.Events {
+		if event.Schedule != nil {
+			options = append(options, WithCronTrigger(event.Schedule.Rate, event.Schedule.Input))
+		}
+	}
+
+	switch fnConfig.Privacy {
+	case "", "public":
+	case privacyPrivate:
+		s.private = true
+	default:
+		return fmt.Errorf("function %q: unknown privacy %q", name, fnConfig.Privacy)
+	}
+
+	optionsErr := s.err
+	s.err = nil
+
+	for idx := range options {
+		options[idx](s)
+	}
+
+	optionsErr, s.err = s.err, optionsErr
+	if optionsErr != nil {
+		return fmt.Errorf("function %q: %w", name, optionsErr)
+	}
+
+	// The environment is declared by the configuration file even if it has no variable.
+	s.setEnv(map[string]string{}, false)
+
+	for _, values := range []map[string]string{config.Provider.Env, fnConfig.Env, config.Provider.Secret, fnConfig.Secret} {
+		if err := expandVariables(values); err != nil {
+			return fmt.Errorf("function %q: %w", name, err)
+		}
+	}
+
+	s.setEnv(config.Provider.Env, false)
+	s.setEnv(fnConfig.Env, false)
+	s.setEnv(config.Provider.Secret, true)
+	s.setEnv(fnConfig.Secret, true)
+
+	return nil
+}
+
+// expandVariables replaces the ${env:NAME} variables of values by the environment variables of the process.
+func expandVariables(values map[string]string) error {
+	for key, value := range values {
+		var err error
+
+		values[key] = configVariableRegexp.ReplaceAllStringFunc(value, func(variable string) string {
+			reference := strings.TrimSpace(variable[2 : len(variable)-1])
+
+			name, ok := strings.CutPrefix(reference, "env:")
+			if !ok {
+				err = errors.Join(err, fmt.Errorf("%s: variable %s is not supported, only ${env:NAME} is", key, variable))
+
+				return variable
+			}
+
+			envValue, ok := os.LookupEnv(strings.TrimSpace(name))
+			if !ok {
+				err = errors.Join(err, fmt.Errorf("%s: %s refers to an unset environment variable", key, variable))
+			}
+
+			return envValue
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// function returns the entry of the function named name, or the only function of the configuration.
+func (c *serverlessConfig) function(name string) (string, functionConfig, error) {
+	if fnConfig, ok := c.Functions[name]; ok {
+		return name, fnConfig, nil
+	}
+
+	if len(c.Functions) == 1 {
+		for onlyName, fnConfig := range c.Functions {
+			return onlyName, fnConfig, nil
+		}
+	}
+
+	names := make([]string, 0, len(c.Functions))
+	for fnName := range c.Functions {
+		names = append(names, fnName)
+	}
+
+	sort.Strings(names)
+
+	return "", functionConfig{}, fmt.Errorf("function %q not found, set its name with WithExecutionContext or "+
+		"use WithFunction to serve one of: %s", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,167 @@
+package local_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configFile = "testdata/serverless.yml"
+
+func TestWithConfigFile(t *testing.T) {
+	t.Setenv("LOCAL_CONFIG_SHARED", "")
+	t.Setenv("LOCAL_CONFIG_PROVIDER", "")
+	t.Setenv("LOCAL_CONFIG_SECRET", "")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		execCtx := core.ExecutionContextFrom(r.Context())
+
+		assert.Equal(t, "users", execCtx.FunctionName)
+		assert.Equal(t, 256, execCtx.MemoryLimitInMB)
+		assert.Equal(t, "my-namespace", execCtx.NamespaceName)
+		assert.Equal(t, "nl-ams", execCtx.Region)
+
+		deadline, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		assert.False(t, deadline.IsZero())
+
+		assert.Equal(t, "users", os.Getenv("LOCAL_CONFIG_SHARED"))
+		assert.Equal(t, "provider", os.Getenv("LOCAL_CONFIG_PROVIDER"))
+		assert.Equal(t, "s3cr3t", os.Getenv("LOCAL_CONFIG_SECRET"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithLogger(discardLogger()),
+		local.WithExecutionContext(core.ExecutionContext{FunctionName: "users"}), local.WithConfigFile(configFile))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// the entry of the function is chosen once all options are applied
+	recorder = httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithLogger(discardLogger()),
+		local.WithConfigFile(configFile), local.WithExecutionContext(core.ExecutionContext{FunctionName: "users"}))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWithConfigFileVariables(t *testing.T) {
+	t.Setenv("LOCAL_CONFIG_API_KEY", "")
+	t.Setenv("LOCAL_CONFIG_URL", "")
+	t.Setenv("LOCAL_CONFIG_SOURCE_KEY", "k3y")
+	t.Setenv("LOCAL_CONFIG_SOURCE_HOST", "db.local")
+
+	path := filepath.Join(t.TempDir(), "serverless.yml")
+	require.NoError(t, os.WriteFile(path, []byte("functions:\n  fn:\n    env:\n"+
+		"      LOCAL_CONFIG_URL: postgres://${env:LOCAL_CONFIG_SOURCE_HOST}:5432\n"+
+		"    secret:\n      LOCAL_CONFIG_API_KEY: ${env:LOCAL_CONFIG_SOURCE_KEY}\n"), 0o600))
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "postgres://db.local:5432", os.Getenv("LOCAL_CONFIG_URL"))
+		assert.Equal(t, "k3y", os.Getenv("LOCAL_CONFIG_API_KEY"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithLogger(discardLogger()), local.WithConfigFile(path))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWithConfigFilePrivateFunction(t *testing.T) {
+	t.Setenv("LOCAL_CONFIG_SHARED", "")
+	t.Setenv("LOCAL_CONFIG_PROVIDER", "")
+	t.Setenv("LOCAL_CONFIG_SECRET", "")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		execCtx := core.ExecutionContextFrom(r.Context())
+
+		assert.Equal(t, "orders", execCtx.FunctionName)
+		assert.Equal(t, 1024, execCtx.MemoryLimitInMB)
+	}
+
+	options := []local.Option{
+		local.WithLogger(discardLogger()),
+		local.WithFunction("orders", handler, local.WithConfigFile(configFile)),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, function.ScwFuncV1(nil), options...)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/orders", http.NoBody)
+	req.Header.Set("X-Auth-Token", "token")
+
+	recorder = httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, function.ScwFuncV1(nil), options...)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestWithConfigFileErrors(t *testing.T) {
+	t.Setenv("LOCAL_CONFIG_SHARED", "")
+	t.Setenv("LOCAL_CONFIG_PROVIDER", "")
+	t.Setenv("LOCAL_CONFIG_SECRET", "")
+
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		options []local.Option
+	}{
+		{name: "missing file", options: []local.Option{local.WithConfigFile(filepath.Join(dir, "missing.yml"))}},
+		{name: "unknown function", options: []local.Option{local.WithConfigFile(configFile)}},
+		{name: "invalid yaml", options: []local.Option{local.WithConfigFile(write("invalid.yml", "functions: ["))}},
+		{
+			name:    "invalid timeout",
+			options: []local.Option{local.WithConfigFile(write("timeout.yml", "functions:\n  fn:\n    timeout: soon\n"))},
+		},
+		{
+			name:    "invalid privacy",
+			options: []local.Option{local.WithConfigFile(write("privacy.yml", "functions:\n  fn:\n    privacy: secret\n"))},
+		},
+		{
+			name: "unsupported variable",
+			options: []local.Option{local.WithConfigFile(write("self.yml",
+				"functions:\n  fn:\n    env:\n      LOCAL_CONFIG_SHARED: ${self:service}\n"))},
+		},
+		{
+			name: "unset environment variable",
+			options: []local.Option{local.WithConfigFile(write("unset.yml",
+				"functions:\n  fn:\n    secret:\n      LOCAL_CONFIG_SECRET: ${env:LOCAL_CONFIG_UNSET}\n"))},
+		},
+		{
+			name: "invalid schedule",
+			options: []local.Option{local.WithConfigFile(write("schedule.yml",
+				"functions:\n  fn:\n    events:\n      - schedule:\n          rate: never\n"))},
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := local.Start(context.Background(), func(w http.ResponseWriter, r *http.Request) {}, tt.options...)
+		assert.ErrorIs(t, err, local.ErrInvalidConfig, tt.name)
+	}
+}
@@ -1,10 +1,9 @@
 package local
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
+	"errors"
 	"io"
-	"log"
 	"net/http"
 	"net/http/httptest"
 
@@ -13,82 +12,205 @@ import (
 )
 
 const (
-	// payloadSizeWarn if the body length in bytes is higher than this value it will trigger warning.
-	// In production this will stop the request and return 500.
-	payloadSizeWarn = 6291456
+	// payloadSizeLimit if the request or response body length in bytes is higher than this value it will trigger
+	// warning. In production, as with WithStrictLimits option, this will stop the request and return an error.
+	payloadSizeLimit = 6291456
 )
 
-// CoreProcessing processes the main core
-func CoreProcessing(httpResp http.ResponseWriter, httpReq *http.Request, handler function.ScwFuncV1) {
-	if core.IsRejectedRequest(httpReq) {
-		log.Default().Println("request will be rejected for calling favico or robots.txt")
+// NewHandler returns an http.Handler processing requests with the handler as the local server does, without
+// listening on any port. Options are applied once: environment and config files are read when the handler is
+// created and invalid options are returned as an error. Triggers are only run by a started server, see Start.
+// The handler can be any versioned function prototype, see function.Handler. It can be nil when functions are
+// mounted with WithFunction.
+func NewHandler[H function.Handler](handler H, options ...Option) (http.Handler, error) {
+	return newHandler(newVersionedHandler(handler), options...)
+}
+
+// newHandler returns the http.Handler processing requests with the normalized handler, see NewHandler.
+func newHandler(handler versionedHandler, options ...Option) (http.Handler, error) {
+	server := newServer(options...)
+	if server.err != nil {
+		return nil, server.err
+	}
+
+	if handler.isNil() && len(server.functions) == 0 {
+		return nil, function.ErrUnsupportedHandler
 	}
 
-	if httpReq.ContentLength > payloadSizeWarn {
-		log.Default().Println("request can be rejected because it's too big")
+	if server.hasTriggers() {
+		server.logger.Warn("triggers are not run without a started server, use local.Start to run them")
 	}
 
-	bodyBytes, err := io.ReadAll(httpReq.Body)
+	return http.HandlerFunc(func(httpResp http.ResponseWriter, httpReq *http.Request) {
+		server.process(httpResp, httpReq, handler)
+	}), nil
+}
+
+// CoreProcessing processes a single request. Options are applied on each call, use NewHandler to process several
+// requests with the same options. It panics if the handler or the options are invalid, see NewHandler.
+func CoreProcessing[H function.Handler](httpResp http.ResponseWriter, httpReq *http.Request, handler H, options ...Option) {
+	processor, err := newHandler(newVersionedHandler(handler), options...)
 	if err != nil {
 		panic(err)
 	}
 
-	formattedRequest := core.FormatEventHTTP(httpReq, bodyBytes)
+	processor.ServeHTTP(httpResp, httpReq)
+}
 
-	invoker := core.FunctionInvoker{}
+// process runs the request through the simulated infrastructure and the handler according to server parameters.
+// Requests matching a mounted function are processed with the parameters of this function.
+func (s *Server) process(httpResp http.ResponseWriter, httpReq *http.Request, handler versionedHandler) {
+	if len(s.functions) > 0 {
+		if mounted, routedReq := s.route(httpReq); mounted != nil {
+			mounted.server.process(httpResp, routedReq, mounted.handler)
+
+			return
+		}
+
+		if handler.isNil() {
+			s.logger.Warn("no function matches the request", "host", httpReq.Host, "path", httpReq.URL.Path)
+			writeInfraError(httpResp, http.StatusNotFound, functionNotFoundMessage)
+
+			return
+		}
+	}
+
+	if s.recorder == nil {
+		s.processRequest(httpResp, httpReq, handler)
+
+		return
+	}
+
+	capture := httptest.NewRecorder()
+	event := s.processRequest(capture, httpReq, handler)
+
+	copyResponse(httpResp, capture)
+
+	if event != nil {
+		if err := s.recorder.record(event, capture, s.masker); err != nil {
+			s.logger.Error("invocation could not be recorded", "error", err)
+		}
+	}
+}
+
+// processRequest runs the request through the simulated infrastructure and the handler, it returns the event given
+// to the handler or nil if the request was rejected before.
+//
+//nolint:funlen
+func (s *Server) processRequest(
+	httpResp http.ResponseWriter,
+	httpReq *http.Request,
+	handler versionedHandler,
+) *core.APIGatewayProxyRequest {
+	if s.cors != nil && s.cors.IsPreflight(httpReq) {
+		InjectEgressHeaders(httpResp)
+		s.cors.WritePreflight(httpResp, httpReq)
+
+		return nil
+	}
+
+	if !s.authorize(httpResp, httpReq) {
+		return nil
+	}
+
+	if core.IsRejectedRequest(httpReq) {
+		s.logger.Warn("request will be rejected for calling favico or robots.txt", "path", httpReq.URL.Path)
+	}
 
-	reqForFaaS, err := invoker.Execute(formattedRequest, core.GetExecutionContext())
+	bodyBytes, err := s.readBody(httpReq.Body)
 	if err != nil {
 		panic(err)
 	}
 
-	reqForFaaS.Host = httpReq.Host
-	_ = SubProcessing(httpResp, reqForFaaS)
+	if len(bodyBytes) > payloadSizeLimit {
+		if s.strictLimits {
+			s.logger.Error("request rejected because it's too big", "size", len(bodyBytes))
+			writeInfraError(httpResp, http.StatusRequestEntityTooLarge, requestTooLargeMessage)
 
-	InjectIngressHeaders(reqForFaaS)
+			return nil
+		}
 
-	writerRecorder := httptest.NewRecorder()
-	handler(writerRecorder, reqForFaaS)
+		s.logger.Warn("request can be rejected because it's too big", "size", len(bodyBytes))
+	}
 
-	// Body is closed but linter reports it.
-	//nolint:bodyclose
-	recorderResp := writerRecorder.Result()
-	defer func() {
-		if recorderResp.Body != nil {
-			recorderResp.Body.Close()
-		}
-	}()
+	formattedRequest := s.eventFormatter.Format(httpReq, bodyBytes)
+
+	invoker := core.FunctionInvoker{}
 
-	coreResp, err := core.GetResponse(recorderResp)
+	reqForFaaS, err := invoker.Execute(formattedRequest, s.executionContext)
 	if err != nil {
 		panic(err)
 	}
 
-	responseBody := coreResp.Body
+	reqForFaaS.Host = httpReq.Host
+	if err := SubProcessing(httpResp, reqForFaaS); err != nil {
+		s.logger.Error("sub-runtime processing failed", "error", err)
+	}
+
+	InjectIngressHeaders(reqForFaaS)
 
-	// If user's handler specifies the parameter isBase64Encoded, we need to transform base64 response to byte array
-	if coreResp.IsBase64Encoded && len(coreResp.Body) > 0 {
-		var bodyString string
-		if err := json.Unmarshal(coreResp.Body, &bodyString); err != nil {
-			panic(err)
+	coreResp, err := s.invoke(reqForFaaS, bodyBytes, handler, formattedRequest)
+	if err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			s.logger.Error("function exceeded its timeout and was stopped", "timeout", s.timeout)
+			writeInfraError(httpResp, http.StatusGatewayTimeout, timeoutMessage)
+		case errors.Is(err, errHandlerPanic):
+			writeInfraError(httpResp, http.StatusInternalServerError, handlerErrorMessage)
+		default:
+			s.logger.Error("handler returned an error", "error", err)
+			writeInfraError(httpResp, http.StatusInternalServerError, handlerErrorMessage)
 		}
 
-		base64Binary, err := base64.StdEncoding.DecodeString(bodyString)
-		if err != nil {
-			panic(err)
+		return &formattedRequest
+	}
+
+	// Base64 encoded bodies, set by the handler or for binary data, are decoded by the infrastructure.
+	responseBody, err := coreResp.DecodedBody()
+	if err != nil {
+		s.logger.Error("handler returned an invalid response", "error", err)
+		writeInfraError(httpResp, http.StatusInternalServerError, handlerErrorMessage)
+
+		return &formattedRequest
+	}
+
+	if len(responseBody) > payloadSizeLimit {
+		if s.strictLimits {
+			s.logger.Error("response rejected because it's too big", "size", len(responseBody))
+			writeInfraError(httpResp, http.StatusInternalServerError, responseTooLargeMessage)
+
+			return &formattedRequest
 		}
 
-		responseBody = base64Binary
+		s.logger.Warn("response can be rejected because it's too big", "size", len(responseBody))
 	}
 
 	core.SetHeaders(reqForFaaS.Header, httpResp.Header())
 
-	httpResp.Header().Set("Access-Control-Allow-Origin", "*")
-	httpResp.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
 	InjectEgressHeaders(httpResp)
 
-	core.SetHeaders(recorderResp.Header, httpResp.Header())
+	core.SetHeaders(coreResp.Headers, httpResp.Header())
+
+	corsPolicy := core.DefaultCORSPolicy()
+	if s.cors != nil {
+		corsPolicy = *s.cors
+	}
+
+	corsPolicy.SetHeaders(httpReq, httpResp.Header())
+
+	core.WriteHTTPResponse(httpResp, responseBody, coreResp.StatusCode)
+
+	s.logger.Debug("request processed", "method", formattedRequest.HTTPMethod, "path", formattedRequest.Path,
+		"status", coreResp.StatusCode)
+
+	return &formattedRequest
+}
+
+// readBody reads the request body, in strict mode reading stops as soon as the payload limit is exceeded.
+func (s *Server) readBody(body io.Reader) ([]byte, error) {
+	if s.strictLimits {
+		body = io.LimitReader(body, payloadSizeLimit+1)
+	}
 
-	core.HydrateHTTPResponse(httpResp, responseBody, coreResp.StatusCode)
+	return io.ReadAll(body)
 }
@@ -0,0 +1,576 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoreProcessingTimeout(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+
+		_, _ = w.Write([]byte("too late"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithTimeout(50*time.Millisecond))
+
+	resp := recorder.Result()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Equal(t, "upstream request timeout", string(body))
+	assert.Equal(t, "envoy", resp.Header.Get("server"))
+}
+
+func TestCoreProcessingTimeoutDeadline(t *testing.T) {
+	t.Parallel()
+
+	const testingMessage = "on time"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+		assert.NoError(t, r.Context().Err())
+
+		_, _ = w.Write([]byte(testingMessage))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithTimeout(time.Minute))
+
+	resp := recorder.Result()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, testingMessage, string(body))
+}
+
+func TestCoreProcessingNoTimeout(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.False(t, ok)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCoreProcessingStrictLimitsRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Fail(t, "handler must not be called for oversized requests")
+	}
+
+	// chunked body: content length is unknown so the limit must be checked on bytes read
+	body := io.LimitReader(fillReader{}, 7<<20)
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(body))
+	req.ContentLength = -1
+
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithStrictLimits())
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Equal(t, "payload too large", recorder.Body.String())
+}
+
+func TestCoreProcessingStrictLimitsResponse(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, 7<<20))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithStrictLimits())
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "response payload too large", recorder.Body.String())
+}
+
+func TestCoreProcessingLimitsWarnOnly(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(make([]byte, 7<<20))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 7<<20, recorder.Body.Len())
+}
+
+type fillReader struct{}
+
+func (fillReader) Read(p []byte) (int, error) {
+	for idx := range p {
+		p[idx] = 'a'
+	}
+
+	return len(p), nil
+}
+
+func TestCoreProcessingPanicRecovery(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		panic("bad request")
+	}
+
+	for _, options := range [][]local.Option{
+		{local.WithPanicRecovery()},
+		{local.WithPanicRecovery(), local.WithTimeout(time.Minute)},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/panic", http.NoBody)
+		recorder := httptest.NewRecorder()
+
+		local.CoreProcessing(recorder, req, handler, options...)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, "internal server error", recorder.Body.String())
+	}
+}
+
+func TestCoreProcessingPanicWithoutRecovery(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		panic("bad request")
+	}
+
+	// with a timeout the handler runs in its own goroutine, its panic must still reach the caller
+	for _, options := range [][]local.Option{nil, {local.WithTimeout(time.Minute)}} {
+		req := httptest.NewRequest(http.MethodGet, "/panic", http.NoBody)
+		recorder := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, "bad request", func() { local.CoreProcessing(recorder, req, handler, options...) })
+	}
+}
+
+func TestCoreProcessingV2(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		assert.Equal(t, http.MethodPost, event.HTTPMethod)
+		assert.Equal(t, "/path", event.Path)
+		assert.Equal(t, "value", event.QueryStringParameters["key"])
+		assert.Equal(t, "request body", event.Body)
+		assert.Equal(t, "activator", event.Headers["K-Proxy-Request"])
+
+		assert.NotEmpty(t, core.RequestIDFrom(ctx))
+		assert.Equal(t, event.Headers["X-Request-Id"], core.RequestIDFrom(ctx))
+		assert.Equal(t, core.GetExecutionContext(), core.ExecutionContextFrom(ctx))
+
+		return core.ResponseHTTP{
+			StatusCode: http.StatusCreated,
+			Body:       json.RawMessage(`"created"`),
+			Headers:    map[string][]string{"X-Custom": {"custom"}},
+		}, nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/path?key=value", strings.NewReader("request body"))
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "created", recorder.Body.String())
+	assert.Equal(t, "custom", recorder.Header().Get("X-Custom"))
+	assert.Equal(t, "envoy", recorder.Header().Get("server"))
+}
+
+func TestCoreProcessingBinaryRequest(t *testing.T) {
+	t.Parallel()
+
+	binaryBody := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	handlerV1 := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, binaryBody, body)
+	}
+
+	handlerV2 := func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		assert.True(t, event.IsBase64Encoded)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(binaryBody), event.Body)
+
+		return core.ResponseHTTP{}, nil
+	}
+
+	for _, processor := range []http.Handler{newProcessor(t, handlerV1), newProcessor(t, handlerV2)} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(binaryBody))
+		req.Header.Set("Content-Type", "image/png")
+
+		recorder := httptest.NewRecorder()
+
+		processor.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestCoreProcessingWithBinaryContentTypes(t *testing.T) {
+	t.Parallel()
+
+	handler := func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		assert.Equal(t, event.Headers["Content-Type"] == "application/vnd.custom", event.IsBase64Encoded)
+
+		return core.ResponseHTTP{}, nil
+	}
+
+	for _, contentType := range []string{"application/vnd.custom", "image/png"} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+		req.Header.Set("Content-Type", contentType)
+
+		recorder := httptest.NewRecorder()
+
+		local.CoreProcessing(recorder, req, handler, local.WithBinaryContentTypes("application/vnd.custom"))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+}
+
+func TestCoreProcessingMultiValue(t *testing.T) {
+	t.Parallel()
+
+	handlerV1 := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string{"text/html", "application/json"}, r.Header.Values("Accept"))
+		assert.Equal(t, []string{"1", "2"}, r.URL.Query()["id"])
+
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+	}
+
+	handlerV2 := func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		assert.Equal(t, []string{"text/html", "application/json"}, event.MultiValueHeaders["Accept"])
+		assert.Equal(t, []string{"1", "2"}, event.MultiValueQueryStringParameters["id"])
+
+		return core.ResponseHTTP{Headers: map[string][]string{"Set-Cookie": {"a=1", "b=2"}}}, nil
+	}
+
+	for _, processor := range []http.Handler{newProcessor(t, handlerV1), newProcessor(t, handlerV2)} {
+		req := httptest.NewRequest(http.MethodGet, "/?id=1&id=2", http.NoBody)
+		req.Header.Add("Accept", "text/html")
+		req.Header.Add("Accept", "application/json")
+
+		recorder := httptest.NewRecorder()
+
+		processor.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, []string{"a=1", "b=2"}, recorder.Header().Values("Set-Cookie"))
+	}
+}
+
+func TestCoreProcessingBinaryResponse(t *testing.T) {
+	t.Parallel()
+
+	binaryBody := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	processors := map[string]http.Handler{
+		"raw": newProcessor(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(binaryBody)
+		}),
+		"write binary": newProcessor(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, core.WriteBinary(w, "image/png", binaryBody))
+		}),
+		"binary response": newProcessor(t, func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+			return core.BinaryResponse(http.StatusOK, "image/png", binaryBody), nil
+		}),
+	}
+
+	for name, processor := range processors {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		recorder := httptest.NewRecorder()
+
+		processor.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code, name)
+		assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"), name)
+		assert.Equal(t, binaryBody, recorder.Body.Bytes(), name)
+	}
+}
+
+func TestCoreProcessingResponseEnvelope(t *testing.T) {
+	t.Parallel()
+
+	const businessData = `{"statusCode": 5, "body": "business data"}`
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(businessData))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, businessData, recorder.Body.String())
+
+	envelopeHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, core.WriteResponse(w, core.ResponseHTTP{
+			StatusCode: http.StatusCreated,
+			Body:       json.RawMessage(`"created"`),
+			Headers:    map[string][]string{"Content-Type": {"text/plain"}},
+		}))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder = httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, envelopeHandler)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, "created", recorder.Body.String())
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+	assert.Empty(t, recorder.Header().Get(core.HeaderResponseEnvelope))
+}
+
+func TestCoreProcessingInvalidResponseEnvelope(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(core.HeaderResponseEnvelope, "true")
+		_, _ = w.Write([]byte(`{"status": 201}`))
+	}
+
+	logs := &bytes.Buffer{}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithLogger(slog.New(slog.NewJSONHandler(logs, nil))))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, logs.String(), `unknown field \"status\"`)
+}
+
+func TestCoreProcessingV2Error(t *testing.T) {
+	t.Parallel()
+
+	handler := function.ScwFuncV2(func(ctx context.Context, event core.APIGatewayProxyRequest) (core.ResponseHTTP, error) {
+		return core.ResponseHTTP{}, errors.New("handler failure")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, "internal server error", recorder.Body.String())
+}
+
+func TestCoreProcessingNilHandler(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	assert.Panics(t, func() { local.CoreProcessing(recorder, req, function.ScwFuncV2(nil)) })
+}
+
+func TestCoreProcessingInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	assert.Panics(t, func() {
+		local.CoreProcessing(recorder, req, handler, local.WithConfigFile("testdata/nonexistent.yml"))
+	})
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(core.Getenv(r.Context(), "NEW_HANDLER_GREETING")))
+	}
+
+	processor, err := local.NewHandler(handler, local.WithEnv(map[string]string{"NEW_HANDLER_GREETING": "hello"}))
+	require.NoError(t, err)
+
+	for idx := 0; idx < 2; idx++ {
+		recorder := httptest.NewRecorder()
+		processor.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "hello", recorder.Body.String())
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewHandlerInvalid(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	_, err := local.NewHandler(handler, local.WithConfigFile("testdata/nonexistent.yml"))
+	assert.ErrorIs(t, err, local.ErrInvalidConfig)
+
+	_, err = local.NewHandler(handler, local.WithPrivateFunction(nil))
+	assert.Error(t, err)
+
+	_, err = local.NewHandler(function.ScwFuncV1(nil))
+	assert.ErrorIs(t, err, function.ErrUnsupportedHandler)
+}
+
+func TestCoreProcessingJSONAdapter(t *testing.T) {
+	t.Parallel()
+
+	type sumRequest struct {
+		Values []int `json:"values"`
+	}
+
+	type sumResponse struct {
+		Sum int `json:"sum"`
+	}
+
+	sum := func(ctx context.Context, req sumRequest) (sumResponse, error) {
+		resp := sumResponse{}
+		for _, value := range req.Values {
+			resp.Sum += value
+		}
+
+		return resp, nil
+	}
+
+	for _, processor := range []http.Handler{newProcessor(t, function.JSON(sum)), newProcessor(t, function.JSONV2(sum))} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"values":[1,2,3]}`))
+		recorder := httptest.NewRecorder()
+
+		processor.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"sum":6}`, recorder.Body.String())
+	}
+}
+
+func TestCoreProcessingExecutionContext(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, core.GetExecutionContext(), core.ExecutionContextFrom(r.Context()))
+		assert.Equal(t, r.Header.Get("X-Request-Id"), core.RequestIDFrom(r.Context()))
+		assert.NotEmpty(t, core.RequestIDFromHTTP(r))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCoreProcessingWithExecutionContext(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		execCtx := core.ExecutionContextFrom(r.Context())
+
+		assert.Equal(t, "my-function", execCtx.FunctionName)
+		assert.Equal(t, 1024, execCtx.MemoryLimitInMB)
+		assert.Equal(t, core.GetExecutionContext().FunctionVersion, execCtx.FunctionVersion)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithExecutionContext(core.ExecutionContext{
+		FunctionName:    "my-function",
+		MemoryLimitInMB: 1024,
+	}))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCoreProcessingWithLogger(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		panic("logged panic")
+	}
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithPanicRecovery(), local.WithLogger(logger))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, buf.String(), "handler panic recovered")
+	assert.Contains(t, buf.String(), "logged panic")
+}
+
+// newProcessor returns the http.Handler processing requests with handler, the test fails if options are invalid.
+func newProcessor[H function.Handler](t *testing.T, handler H, options ...local.Option) http.Handler {
+	t.Helper()
+
+	processor, err := local.NewHandler(handler, options...)
+	require.NoError(t, err)
+
+	return processor
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
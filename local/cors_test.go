@@ -0,0 +1,79 @@
+package local_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoreProcessingWithCORS(t *testing.T) {
+	t.Parallel()
+
+	invoked := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		invoked = true
+
+		w.Header().Set("X-Total", "42")
+	}
+
+	policy := core.CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type"},
+		ExposedHeaders: []string{"X-Total"},
+	}
+
+	req := httptest.NewRequest(http.MethodOptions, "/", http.NoBody)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithCORS(policy))
+
+	assert.False(t, invoked)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "envoy", recorder.Header().Get("Server"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set("Origin", "https://app.example.com")
+
+	recorder = httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithCORS(policy))
+
+	assert.True(t, invoked)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{"https://app.example.com"}, recorder.Header().Values("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Total", recorder.Header().Get("Access-Control-Expose-Headers"))
+
+	req = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set("Origin", "https://evil.example.com")
+
+	recorder = httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithCORS(policy))
+
+	assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCoreProcessingHandlerCORSHeaders(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "https://app.example.com")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler)
+
+	assert.Equal(t, []string{"https://app.example.com"}, recorder.Header().Values("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Content-Type", recorder.Header().Get("Access-Control-Allow-Headers"))
+}
@@ -0,0 +1,215 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCronSchedule is returned when a CRON schedule can not be parsed.
+var ErrInvalidCronSchedule = errors.New("invalid cron schedule")
+
+// cronFieldsCount is the number of fields of a standard CRON expression.
+const cronFieldsCount = 5
+
+// cronSearchLimit bounds the search of the next activation of a schedule that never matches, e.g. 30 February.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// cronField describes the allowed values of a field of a CRON expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDay    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronWeekday = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronMacros are the shortcuts accepted in place of the five fields of a CRON expression.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule is a parsed CRON expression. Each field is a bit set of the allowed values.
+type cronSchedule struct {
+	minute, hour, day, month, weekday uint64
+
+	// dayRestricted and weekdayRestricted are used to apply the standard rule: when both day fields are
+	// restricted, a time matches if any of them matches.
+	dayRestricted, weekdayRestricted bool
+
+	// every is set for "@every <duration>" schedules, not standard but handy to test triggers quickly.
+	every time.Duration
+}
+
+// parseCronSchedule parses a standard CRON expression with five fields: minute, hour, day of month, month and
+// day of week. Lists, ranges, steps, month and day names and macros like @daily are supported.
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("%w: %q: invalid duration", ErrInvalidCronSchedule, expr)
+		}
+
+		return &cronSchedule{every: every}, nil
+	}
+
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != cronFieldsCount {
+		return nil, fmt.Errorf("%w: %q: expected %d fields, got %d", ErrInvalidCronSchedule, expr, cronFieldsCount, len(fields))
+	}
+
+	// As in standard CRON, a field starting with "*", like "*/2", does not restrict days.
+	schedule := &cronSchedule{
+		dayRestricted:     !strings.HasPrefix(fields[2], "*"),
+		weekdayRestricted: !strings.HasPrefix(fields[4], "*"),
+	}
+
+	targets := []*uint64{&schedule.minute, &schedule.hour, &schedule.day, &schedule.month, &schedule.weekday}
+	definitions := []cronField{cronMinute, cronHour, cronDay, cronMonth, cronWeekday}
+
+	for idx := range fields {
+		bits, err := definitions[idx].parse(fields[idx])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", ErrInvalidCronSchedule, expr, err.Error())
+		}
+
+		*targets[idx] = bits
+	}
+
+	// 7 is an alias of sunday
+	if schedule.weekday&(1<<7) != 0 {
+		schedule.weekday |= 1
+	}
+
+	return schedule, nil
+}
+
+// parse returns the bit set of the values allowed by a field, e.g. "1-5", "*/15" or "mon,wed,fri".
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			var err error
+
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q for %s", stepPart, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+
+			if low, err = f.value(lowPart); err != nil {
+				return 0, err
+			}
+
+			high = low
+
+			if isRange {
+				if high, err = f.value(highPart); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q for %s", rangePart, f.name)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single value of a field, names are case insensitive.
+func (f cronField) value(raw string) (int, error) {
+	if value, ok := f.names[strings.ToLower(raw)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < f.min || value > f.max {
+		return 0, fmt.Errorf("invalid value %q for %s, expected %d-%d", raw, f.name, f.min, f.max)
+	}
+
+	return value, nil
+}
+
+// next returns the first activation of the schedule strictly after t, or the zero time if there is none.
+func (c *cronSchedule) next(t time.Time) time.Time {
+	if c.every > 0 {
+		return t.Add(c.every)
+	}
+
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for next.Before(limit) {
+		switch {
+		case c.month&(1<<uint(next.Month())) == 0:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.matchDay(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case c.hour&(1<<uint(next.Hour())) == 0:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case c.minute&(1<<uint(next.Minute())) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay applies the standard rule of CRON for day of month and day of week fields.
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	dayMatch := c.day&(1<<uint(t.Day())) != 0
+	weekdayMatch := c.weekday&(1<<uint(t.Weekday())) != 0
+
+	if c.dayRestricted && c.weekdayRestricted {
+		return dayMatch || weekdayMatch
+	}
+
+	return dayMatch && weekdayMatch
+}
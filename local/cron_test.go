@@ -0,0 +1,193 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronScheduleNext(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2023, time.March, 15, 10, 32, 20, 0, time.UTC) // a wednesday
+
+	testCases := map[string]time.Time{
+		"* * * * *":          time.Date(2023, time.March, 15, 10, 33, 0, 0, time.UTC),
+		"*/15 * * * *":       time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC),
+		"0 9-17/4 * * *":     time.Date(2023, time.March, 15, 13, 0, 0, 0, time.UTC),
+		"30 8 * * mon-fri":   time.Date(2023, time.March, 16, 8, 30, 0, 0, time.UTC),
+		"0 0 1 jan *":        time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * * 7":         time.Date(2023, time.March, 19, 12, 0, 0, 0, time.UTC),
+		"0 0 20 * 1":         time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC),
+		"5,10 11 * * *":      time.Date(2023, time.March, 15, 11, 5, 0, 0, time.UTC),
+		"@hourly":            time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC),
+		"@daily":             time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC),
+		"@every 90s":         from.Add(90 * time.Second),
+		"0 0 29 feb *":       time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		" 0   0 * *   * ":    time.Date(2023, time.March, 16, 0, 0, 0, 0, time.UTC),
+		"0 0 1,15 * wed":     time.Date(2023, time.March, 22, 0, 0, 0, 0, time.UTC),
+		"59 23 31 12 *":      time.Date(2023, time.December, 31, 23, 59, 0, 0, time.UTC),
+		"0-10/5 10-11 * * *": time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC),
+		"0 0 */2 * 1":        time.Date(2023, time.March, 27, 0, 0, 0, 0, time.UTC),
+		"0 0 * * */3":        time.Date(2023, time.March, 18, 0, 0, 0, 0, time.UTC),
+	}
+
+	for expr, expected := range testCases {
+		schedule, err := parseCronSchedule(expr)
+		require.NoError(t, err, expr)
+
+		assert.Equal(t, expected, schedule.next(from), expr)
+	}
+}
+
+func TestParseCronScheduleNever(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := parseCronSchedule("0 0 30 feb *")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.next(time.Now()).IsZero())
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	t.Parallel()
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"@every",
+		"@every -1s",
+	}
+
+	for _, expr := range invalid {
+		_, err := parseCronSchedule(expr)
+		assert.ErrorIs(t, err, ErrInvalidCronSchedule, expr)
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestCronTrigger(t *testing.T) {
+	t.Parallel()
+
+	bodies := make(chan string, 10)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		bodies <- string(body)
+	}
+
+	logs := &syncBuffer{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := Start(ctx, handler,
+		WithCronTrigger("@every 100ms", map[string]string{"job": "cleanup"}),
+		WithLogger(slog.New(slog.NewTextHandler(logs, nil))),
+	)
+	require.NoError(t, err)
+
+	select {
+	case body := <-bodies:
+		assert.JSONEq(t, `{"job":"cleanup"}`, body)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "cron trigger was not invoked")
+	}
+
+	cancel()
+	require.NoError(t, server.Wait())
+
+	assert.Contains(t, logs.String(), "cron trigger invoked")
+	assert.Contains(t, logs.String(), "status=200")
+}
+
+func TestCronTriggerPrivateFunction(t *testing.T) {
+	t.Parallel()
+
+	invoked := make(chan struct{}, 10)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		invoked <- struct{}{}
+	}
+
+	keyPair, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	logs := &syncBuffer{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := Start(ctx, handler,
+		WithPrivateFunction(keyPair),
+		WithCronTrigger("@every 100ms", nil),
+		WithLogger(slog.New(slog.NewTextHandler(logs, nil))),
+	)
+	require.NoError(t, err)
+
+	select {
+	case <-invoked:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "cron trigger was not invoked")
+	}
+
+	// HTTP requests still need a token
+	//nolint:noctx
+	resp, err := http.Get(server.URL())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	cancel()
+	require.NoError(t, server.Wait())
+
+	assert.Contains(t, logs.String(), "cron trigger invoked")
+	assert.NotContains(t, logs.String(), "cron trigger invocation failed")
+}
+
+func TestCronTriggerInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Start(context.Background(), func(w http.ResponseWriter, r *http.Request) {},
+		WithCronTrigger("invalid", nil))
+	assert.ErrorIs(t, err, ErrInvalidCronSchedule)
+}
@@ -0,0 +1,259 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+const (
+	// maskedSecret replaces the values of secrets in logs and recorded invocations.
+	maskedSecret = "***"
+
+	// runtimeEnvPrefix is the prefix of environment variables set by the runtime, they are always declared.
+	runtimeEnvPrefix = "SCW_"
+)
+
+// ErrInvalidEnvFile is returned when an env file can not be parsed.
+var ErrInvalidEnvFile = errors.New("invalid env file")
+
+// WithEnv declares environment variables of the function, as configured on the deployed function. They are set in
+// the process environment before serving. Once the environment is declared, reading another variable with
+// core.Getenv or core.LookupEnv logs a warning. Variables read with os.Getenv can not be detected and are not
+// reported.
+func WithEnv(env map[string]string) Option {
+	return func(s *Server) {
+		s.setEnv(env, false)
+	}
+}
+
+// WithSecrets declares secret environment variables of the function, see WithEnv. Their values are masked in the
+// logs of the local server and in recorded invocations. Logs written by the handler, e.g. with the logging package
+// or by function.JSON, do not go through the local server and are not masked.
+func WithSecrets(secrets map[string]string) Option {
+	return func(s *Server) {
+		s.setEnv(secrets, true)
+	}
+}
+
+// WithEnvFile declares environment variables of the function read from a file with one KEY=value per line, like
+// ".env" files. Empty lines and lines starting with # are ignored, values can be quoted.
+func WithEnvFile(path string) Option {
+	return func(s *Server) {
+		env, err := readEnvFile(path)
+		if err != nil {
+			s.err = errors.Join(s.err, err)
+
+			return
+		}
+
+		s.setEnv(env, false)
+	}
+}
+
+// readEnvFile parses an env file.
+func readEnvFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%w %s:%d: expected KEY=value", ErrInvalidEnvFile, path, lineNumber)
+		}
+
+		value, err := unquoteEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w %s:%d: %w", ErrInvalidEnvFile, path, lineNumber, err)
+		}
+
+		env[key] = value
+	}
+
+	return env, scanner.Err()
+}
+
+// unquoteEnvValue removes quotes around a value, escape sequences are interpreted in double quoted values.
+func unquoteEnvValue(value string) (string, error) {
+	const minQuotedLength = 2
+
+	if len(value) < minQuotedLength {
+		return value, nil
+	}
+
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		return strconv.Unquote(value)
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1], nil
+	default:
+		return value, nil
+	}
+}
+
+// setEnv declares environment variables of the function, secrets are also declared in secrets.
+func (s *Server) setEnv(values map[string]string, secret bool) {
+	if values == nil {
+		return
+	}
+
+	if s.env == nil {
+		s.env = map[string]string{}
+	}
+
+	if secret && s.secrets == nil {
+		s.secrets = map[string]string{}
+	}
+
+	for key, value := range values {
+		s.env[key] = value
+
+		if secret {
+			s.secrets[key] = value
+		}
+	}
+}
+
+// applyEnv sets the environment variables of the server and of its mounted functions in the process environment,
+// as handlers read them with os.Getenv. Functions share the process so a variable declared with different values
+// by several functions is reported.
+func (s *Server) applyEnv() {
+	declaredBy := map[string]string{}
+
+	for _, server := range s.servers() {
+		keys := make([]string, 0, len(server.env))
+		for key := range server.env {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value := server.env[key]
+
+			if previous, ok := declaredBy[key]; ok && os.Getenv(key) != value {
+				s.logger.Warn("environment variable declared with different values, functions share the process "+
+					"environment", "key", key, "functions", []string{previous, server.executionContext.FunctionName})
+			}
+
+			if err := os.Setenv(key, value); err != nil {
+				s.err = errors.Join(s.err, fmt.Errorf("environment variable %q: %w", key, err))
+			}
+
+			declaredBy[key] = server.executionContext.FunctionName
+		}
+	}
+}
+
+// maskSecrets hides the values of the secrets of the server and of its mounted functions in the logs of the server
+// and in recorded invocations, the loggers used by handlers are not wrapped.
+func (s *Server) maskSecrets() {
+	secrets := map[string]bool{}
+
+	for _, server := range s.servers() {
+		for _, value := range server.secrets {
+			if value == "" {
+				continue
+			}
+
+			// Secrets written by the handler in a JSON body are escaped, e.g. '&' as \u0026.
+			for _, variant := range jsonEscapedVariants(value) {
+				secrets[variant] = true
+			}
+		}
+	}
+
+	if len(secrets) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(secrets))
+	for value := range secrets {
+		values = append(values, value)
+	}
+
+	// Longest values first so a secret containing another one is entirely masked.
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	pairs := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		pairs = append(pairs, value, maskedSecret)
+	}
+
+	masker := strings.NewReplacer(pairs...)
+
+	for _, server := range s.servers() {
+		server.masker = masker
+		server.logger = newMaskingLogger(server.logger, masker)
+	}
+}
+
+// jsonEscapedVariants returns value and its forms once escaped in a JSON string, with and without HTML escaping.
+func jsonEscapedVariants(value string) []string {
+	variants := []string{value}
+
+	for _, escapeHTML := range []bool{true, false} {
+		encoded := &bytes.Buffer{}
+
+		encoder := json.NewEncoder(encoded)
+		encoder.SetEscapeHTML(escapeHTML)
+
+		// encoding a string never fails
+		_ = encoder.Encode(value)
+
+		escaped := strings.TrimSuffix(encoded.String(), "\n")
+		variants = append(variants, escaped[1:len(escaped)-1])
+	}
+
+	return variants
+}
+
+// servers returns the server and the servers of its mounted functions.
+func (s *Server) servers() []*Server {
+	servers := []*Server{s}
+	for _, mounted := range s.functions {
+		servers = append(servers, mounted.server)
+	}
+
+	return servers
+}
+
+// envContext returns a copy of ctx in which core.Getenv and core.LookupEnv report variables not declared in the
+// configuration of the function, each variable is reported once. Reads with os.Getenv are not seen.
+func (s *Server) envContext(ctx context.Context) context.Context {
+	if s.env == nil {
+		return ctx
+	}
+
+	return core.WithEnvReporter(ctx, func(key string) {
+		if _, ok := s.env[key]; ok || strings.HasPrefix(key, runtimeEnvPrefix) {
+			return
+		}
+
+		if _, reported := s.undeclaredEnv.LoadOrStore(key, true); !reported {
+			s.logger.Warn("handler read an environment variable not declared in the configuration of the function",
+				"key", key)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnv(t *testing.T) {
+	t.Setenv("LOCAL_ENV_PLAIN", "")
+	t.Setenv("LOCAL_ENV_SECRET", "")
+	t.Setenv("LOCAL_ENV_FILE", "")
+	t.Setenv("LOCAL_ENV_QUOTED", "")
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte(
+		"# comment\n\nexport LOCAL_ENV_FILE=from-file\nLOCAL_ENV_QUOTED=\"line\\nbreak\"\n"), 0o600))
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "plain", os.Getenv("LOCAL_ENV_PLAIN"))
+		assert.Equal(t, "hunter2", core.Getenv(r.Context(), "LOCAL_ENV_SECRET"))
+		assert.Equal(t, "from-file", os.Getenv("LOCAL_ENV_FILE"))
+		assert.Equal(t, "line\nbreak", os.Getenv("LOCAL_ENV_QUOTED"))
+		core.Getenv(r.Context(), core.EnvFunctionName)
+
+		core.Getenv(r.Context(), "LOCAL_ENV_UNDECLARED")
+		core.Getenv(r.Context(), "LOCAL_ENV_UNDECLARED")
+
+		panic("secret is hunter2")
+	}
+
+	logs := &bytes.Buffer{}
+	recordFile := filepath.Join(t.TempDir(), "record.jsonl")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"password":"hunter2"}`))
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler,
+		local.WithLogger(slog.New(slog.NewJSONHandler(logs, nil))),
+		local.WithPanicRecovery(),
+		local.WithRecorder(recordFile),
+		local.WithEnv(map[string]string{"LOCAL_ENV_PLAIN": "plain"}),
+		local.WithSecrets(map[string]string{"LOCAL_ENV_SECRET": "hunter2"}),
+		local.WithEnvFile(envFile))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	assert.Contains(t, logs.String(), "handler panic recovered")
+	assert.NotContains(t, logs.String(), "hunter2")
+	assert.Contains(t, logs.String(), "secret is ***")
+	assert.Equal(t, 1, strings.Count(logs.String(), "LOCAL_ENV_UNDECLARED"))
+	assert.NotContains(t, logs.String(), core.EnvFunctionName)
+
+	recorded, err := os.ReadFile(recordFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(recorded), "hunter2")
+	assert.Contains(t, string(recorded), "***")
+}
+
+func TestWithSecretsRecordEscaped(t *testing.T) {
+	t.Setenv("LOCAL_ENV_ESCAPED_SECRET", "")
+
+	const secret = `p&ss<word>"\`
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"escaped": secret})
+		_, _ = w.Write([]byte("raw=" + secret))
+	}
+
+	recordFile := filepath.Join(t.TempDir(), "record.jsonl")
+
+	req := httptest.NewRequest(http.MethodPost, "/?secret="+url.QueryEscape(secret), strings.NewReader("secret="+secret))
+	req.Header.Set("X-Secret", secret)
+
+	local.CoreProcessing(httptest.NewRecorder(), req, handler,
+		local.WithRecorder(recordFile),
+		local.WithSecrets(map[string]string{"LOCAL_ENV_ESCAPED_SECRET": secret}))
+
+	recorded, err := os.ReadFile(recordFile)
+	require.NoError(t, err)
+
+	var invocation local.RecordedInvocation
+	require.NoError(t, json.Unmarshal(recorded, &invocation))
+
+	assert.Equal(t, "secret=***", invocation.Event.Body)
+	assert.Equal(t, "***", invocation.Event.Headers["X-Secret"])
+	assert.Equal(t, "***", invocation.Event.QueryStringParameters["secret"])
+
+	var body string
+	require.NoError(t, json.Unmarshal(invocation.Response.Body, &body))
+	assert.Equal(t, "{\"escaped\":\"***\"}\nraw=***", body)
+
+	for _, leaked := range []string{"p&ss", `p\u0026ss`, "word"} {
+		assert.NotContains(t, string(recorded), leaked)
+	}
+}
+
+func TestWithEnvNotDeclared(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		core.Getenv(r.Context(), "LOCAL_ENV_UNDECLARED")
+	}
+
+	logs := &bytes.Buffer{}
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, handler, local.WithLogger(slog.New(slog.NewJSONHandler(logs, nil))))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, logs.String(), "LOCAL_ENV_UNDECLARED")
+}
+
+func TestWithEnvFileErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"no separator": "KEY\n",
+		"empty key":    "=value\n",
+		"invalid key":  "MY KEY=value\n",
+		"bad quotes":   "KEY=\"unterminated\\\"\n",
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-"))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := local.Start(context.Background(), func(w http.ResponseWriter, r *http.Request) {}, local.WithEnvFile(path))
+		assert.ErrorIs(t, err, local.ErrInvalidEnvFile, name)
+	}
+
+	_, err := local.Start(context.Background(), func(w http.ResponseWriter, r *http.Request) {},
+		local.WithEnvFile(filepath.Join(dir, "missing")))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
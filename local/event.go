@@ -0,0 +1,146 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+)
+
+// ErrInvalidEvent is returned when an event document is neither an APIGatewayProxyRequest nor a CoreRuntimeRequest.
+var ErrInvalidEvent = errors.New("invalid event document")
+
+// ParseEvent decodes an event document, either a core.APIGatewayProxyRequest or a core.CoreRuntimeRequest as sent by
+// the core runtime. The execution context is only returned for a core.CoreRuntimeRequest.
+func ParseEvent(eventJSON []byte) (core.APIGatewayProxyRequest, *core.ExecutionContext, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(eventJSON, &document); err != nil {
+		return core.APIGatewayProxyRequest{}, nil, errors.Join(ErrInvalidEvent, err)
+	}
+
+	if rawEvent, ok := document["event"]; ok && bytes.HasPrefix(bytes.TrimSpace(rawEvent), []byte("{")) {
+		var runtimeRequest core.CoreRuntimeRequest
+		if err := json.Unmarshal(eventJSON, &runtimeRequest); err != nil {
+			return core.APIGatewayProxyRequest{}, nil, errors.Join(ErrInvalidEvent, err)
+		}
+
+		var execCtx *core.ExecutionContext
+		if _, ok := document["context"]; ok {
+			execCtx = &runtimeRequest.Context
+		}
+
+		return runtimeRequest.Event, execCtx, nil
+	}
+
+	var event core.APIGatewayProxyRequest
+	if err := json.Unmarshal(eventJSON, &event); err != nil {
+		return core.APIGatewayProxyRequest{}, nil, errors.Join(ErrInvalidEvent, err)
+	}
+
+	return event, nil, nil
+}
+
+// NewEventRequest rebuilds the HTTP request described by an event. The returned request has no host, set its URL
+// to send it to a server.
+func NewEventRequest(event *core.APIGatewayProxyRequest) (*http.Request, error) {
+	body := []byte(event.Body)
+
+	if event.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		body = decoded
+	}
+
+	query := url.Values{}
+	for key, value := range event.QueryStringParameters {
+		query.Set(key, value)
+	}
+
+	for key, values := range event.MultiValueQueryStringParameters {
+		query[key] = values
+	}
+
+	method := event.HTTPMethod
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	path := event.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	reqURL := url.URL{Path: path, RawQuery: query.Encode()}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, reqURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range event.Headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, values := range event.MultiValueHeaders {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	return req, nil
+}
+
+// InvokeEvent invokes the handler once with an event document, see ParseEvent, without starting a server.
+// The returned response is the one CoreProcessing would send to the caller. The execution context of a
+// core.CoreRuntimeRequest is given to the handler unless WithExecutionContext is used.
+func InvokeEvent[H function.Handler](handler H, eventJSON []byte, options ...Option) (*http.Response, error) {
+	if err := function.Validate(handler); err != nil {
+		return nil, err
+	}
+
+	event, execCtx, err := ParseEvent(eventJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if execCtx != nil {
+		options = append([]Option{WithExecutionContext(*execCtx)}, options...)
+	}
+
+	server := newServer(options...)
+	if server.err != nil {
+		return nil, server.err
+	}
+
+	req, err := NewEventRequest(&event)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := httptest.NewRecorder()
+	server.process(recorder, withLocalHost(req), newVersionedHandler(handler))
+
+	return recorder.Result(), nil
+}
+
+// withLocalHost sets the host of a request rebuilt from an event when the event did not have one.
+func withLocalHost(req *http.Request) *http.Request {
+	if req.Host == "" {
+		req.Host = "localhost"
+	}
+
+	return req
+}
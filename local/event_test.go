@@ -0,0 +1,81 @@
+package local_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeEventAPIGateway(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/items/1", r.URL.Path)
+		assert.Equal(t, "full", r.URL.Query().Get("mode"))
+		assert.Equal(t, "token", r.Header.Get("X-Token"))
+		assert.Equal(t, "payload", string(body))
+
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("accepted"))
+	}
+
+	resp, err := local.InvokeEvent(handler, []byte(`{
+		"path": "/items/1",
+		"httpMethod": "PUT",
+		"headers": {"X-Token": "token"},
+		"queryStringParameters": {"mode": "full"},
+		"body": "cGF5bG9hZA==",
+		"isBase64Encoded": true
+	}`))
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "accepted", string(body))
+	assert.Equal(t, "envoy", resp.Header.Get("server"))
+}
+
+func TestInvokeEventCoreRuntimeRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		execCtx := core.ExecutionContextFrom(r.Context())
+
+		assert.Equal(t, "from-event", execCtx.FunctionName)
+		assert.Equal(t, 256, execCtx.MemoryLimitInMB)
+		assert.Equal(t, http.MethodPost, r.Method)
+	}
+
+	resp, err := local.InvokeEvent(handler, []byte(`{
+		"event": {"path": "/", "httpMethod": "POST", "body": "{}"},
+		"context": {"memoryLimitInMb": 256, "functionName": "from-event", "functionVersion": "1.0.0"},
+		"handlerName": "Handle"
+	}`))
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestParseEventInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, document := range []string{`not json`, `[]`, `{"event": {"path": 1}}`} {
+		_, _, err := local.ParseEvent([]byte(document))
+		assert.ErrorIs(t, err, local.ErrInvalidEvent, document)
+	}
+}
@@ -0,0 +1,216 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/scaleway/serverless-functions-go/framework/function"
+)
+
+// functionNotFoundMessage is the body returned when no function of the namespace matches the request.
+const functionNotFoundMessage = "function not found"
+
+// ErrInvalidFunction is returned when a function mounted on the local server is misconfigured.
+var ErrInvalidFunction = errors.New("invalid function")
+
+// functionNameRegexp matches function names accepted by Scaleway, which are also valid host name labels.
+var functionNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// mountedFunction is a function served alongside others by the same local server, as in a namespace.
+type mountedFunction struct {
+	name    string
+	handler versionedHandler
+	options []Option
+
+	// server holds the parameters of the function, inherited from the local server and overridden by options.
+	server *Server
+}
+
+// WithFunction mounts another function on the local server, as a namespace exposes several functions. The function
+// is served under the path prefix /<name>, which is removed from the path given to the handler, and on host names
+// whose first label is <name> or starts with "<name>-" like deployed function endpoints.
+//
+// The function inherits the parameters of the server and options only apply to it, e.g. WithExecutionContext or
+// WithCronTrigger. The function name of its execution context defaults to name.
+func WithFunction[H function.Handler](name string, handler H, options ...Option) Option {
+	return func(s *Server) {
+		if !functionNameRegexp.MatchString(name) {
+			s.err = errors.Join(s.err, fmt.Errorf("%w: name %q must be lowercase alphanumeric characters or '-'",
+				ErrInvalidFunction, name))
+
+			return
+		}
+
+		if err := function.Validate(handler); err != nil {
+			s.err = errors.Join(s.err, fmt.Errorf("function %q: %w", name, err))
+
+			return
+		}
+
+		for _, mounted := range s.functions {
+			if mounted.name == name {
+				s.err = errors.Join(s.err, fmt.Errorf("%w: %q is mounted twice", ErrInvalidFunction, name))
+
+				return
+			}
+		}
+
+		s.functions = append(s.functions, &mountedFunction{name: name, handler: newVersionedHandler(handler), options: options})
+	}
+}
+
+// ServeFunctions serves several functions on a local webserver, each one mounted with WithFunction. Requests that
+// do not match any function get a 404 error. See ServeHandler for the behavior of the server.
+// To serve functions with handlers of different versions, mount them with WithFunction options.
+func ServeFunctions[H function.Handler](functions map[string]H, options ...Option) {
+	server, err := StartFunctions(context.Background(), functions, options...)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Using port:", server.Port())
+
+	for _, mounted := range server.functions {
+		server.logger.Info("serving function", "function", mounted.name, "url", server.URL()+"/"+mounted.name)
+	}
+
+	if err := server.Wait(); err != nil {
+		panic(err)
+	}
+}
+
+// StartFunctions serves several functions in the background, each one mounted with WithFunction. See Start.
+func StartFunctions[H function.Handler](ctx context.Context, functions map[string]H, options ...Option) (*Server, error) {
+	if len(functions) == 0 {
+		return nil, fmt.Errorf("%w: no function to serve", ErrInvalidFunction)
+	}
+
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	mountOptions := make([]Option, 0, len(options)+len(names))
+	mountOptions = append(mountOptions, options...)
+
+	for _, name := range names {
+		mountOptions = append(mountOptions, WithFunction(name, functions[name]))
+	}
+
+	return start(ctx, versionedHandler{}, mountOptions...)
+}
+
+// mountFunctions creates the parameters of mounted functions once the options of the server are applied.
+func (s *Server) mountFunctions() {
+	for _, mounted := range s.functions {
+		server := &Server{
+			port:             s.port,
+			timeout:          s.timeout,
+			strictLimits:     s.strictLimits,
+			panicRecovery:    s.panicRecovery,
+			private:          s.private,
+			keyPair:          s.keyPair,
+			cors:             s.cors,
+			eventFormatter:   s.eventFormatter,
+			executionContext: s.executionContext,
+			logger:           s.logger.With("function", mounted.name),
+			recorder:         s.recorder,
+			env:              maps.Clone(s.env),
+			secrets:          maps.Clone(s.secrets),
+		}
+
+		server.executionContext.FunctionName = mounted.name
+
+		for idx := range mounted.options {
+			mounted.options[idx](server)
+		}
+
+		server.applyConfigFiles()
+
+		if len(server.functions) > 0 {
+			server.err = errors.Join(server.err, fmt.Errorf("%w: functions can not be nested", ErrInvalidFunction))
+		}
+
+		if server.err != nil {
+			s.err = errors.Join(s.err, fmt.Errorf("function %q: %w", mounted.name, server.err))
+		}
+
+		mounted.server = server
+	}
+}
+
+// route returns the function matching the request, by host name first then by path prefix. The request is
+// returned with the path prefix of the function removed.
+func (s *Server) route(httpReq *http.Request) (*mountedFunction, *http.Request) {
+	if mounted := s.routeHost(httpReq.Host); mounted != nil {
+		return mounted, httpReq
+	}
+
+	for _, mounted := range s.functions {
+		prefix := "/" + mounted.name
+		if httpReq.URL.Path != prefix && !strings.HasPrefix(httpReq.URL.Path, prefix+"/") {
+			continue
+		}
+
+		routedReq := httpReq.Clone(httpReq.Context())
+		routedReq.URL.Path = strings.TrimPrefix(httpReq.URL.Path, prefix)
+		routedReq.URL.RawPath = ""
+
+		if routedReq.URL.Path == "" {
+			routedReq.URL.Path = "/"
+		}
+
+		routedReq.RequestURI = routedReq.URL.RequestURI()
+
+		return mounted, routedReq
+	}
+
+	return nil, httpReq
+}
+
+// routeHost returns the function matching the first label of the host name, the longest name wins when several
+// functions match, e.g. "api-v2" over "api" for "api-v2-namespace.functions.fnc.fr-par.scw.cloud".
+func (s *Server) routeHost(host string) *mountedFunction {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	label, _, _ := strings.Cut(host, ".")
+
+	var match *mountedFunction
+
+	for _, mounted := range s.functions {
+		if label != mounted.name && !strings.HasPrefix(label, mounted.name+"-") {
+			continue
+		}
+
+		if match == nil || len(mounted.name) > len(match.name) {
+			match = mounted
+		}
+	}
+
+	return match
+}
+
+// startFunctionTriggers runs the triggers of mounted functions until ctx is done.
+func (s *Server) startFunctionTriggers(ctx context.Context) {
+	for _, mounted := range s.functions {
+		mounted.server.startTriggers(ctx, mounted.handler)
+	}
+}
+
+// waitFunctionTriggers waits for the triggers of mounted functions to stop.
+func (s *Server) waitFunctionTriggers() {
+	for _, mounted := range s.functions {
+		mounted.server.triggersWG.Wait()
+	}
+}
@@ -0,0 +1,155 @@
+package local_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoFunction writes the function name of the execution context and the path of the request.
+func echoFunction(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte(core.ExecutionContextFrom(r.Context()).FunctionName + " " + r.URL.Path))
+}
+
+func TestCoreProcessingWithFunction(t *testing.T) {
+	t.Parallel()
+
+	options := []local.Option{
+		local.WithLogger(discardLogger()),
+		local.WithFunction("users", echoFunction),
+		local.WithFunction("orders", echoFunction, local.WithExecutionContext(core.ExecutionContext{
+			FunctionName:    "orders-v2",
+			MemoryLimitInMB: 512,
+		})),
+		local.WithFunction("orders-api", echoFunction),
+	}
+
+	tests := []struct {
+		name     string
+		host     string
+		path     string
+		expected string
+	}{
+		{name: "path prefix", path: "/users/42?verbose=1", expected: "users /42"},
+		{name: "path root", path: "/users", expected: "users /"},
+		{name: "execution context", path: "/orders/", expected: "orders-v2 /"},
+		{name: "prefix must be a path segment", path: "/usersfoo", expected: "root /usersfoo"},
+		{name: "host name", host: "users-namespace.functions.fnc.fr-par.scw.cloud", path: "/42", expected: "users /42"},
+		{name: "longest host name", host: "orders-api-namespace.functions.fnc.fr-par.scw.cloud", path: "/", expected: "orders-api /"},
+		{name: "host with port", host: "users:8080", path: "/users", expected: "users /users"},
+		{name: "root handler", path: "/", expected: "root /"},
+	}
+
+	root := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("root " + r.URL.Path))
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+
+			recorder := httptest.NewRecorder()
+
+			local.CoreProcessing(recorder, req, root, options...)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tt.expected, recorder.Body.String())
+		})
+	}
+}
+
+func TestCoreProcessingFunctionNotFound(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/unknown", http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	local.CoreProcessing(recorder, req, function.ScwFuncV1(nil), local.WithLogger(discardLogger()), local.WithFunction("users", echoFunction))
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "envoy", recorder.Header().Get("Server"))
+}
+
+func TestStartFunctions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.StartFunctions(ctx, map[string]function.ScwFuncV1{
+		"users":  echoFunction,
+		"orders": echoFunction,
+	}, local.WithLogger(discardLogger()))
+	require.NoError(t, err)
+
+	for _, name := range []string{"users", "orders"} {
+		//nolint:noctx
+		resp, err := http.Get(server.URL() + "/" + name + "/list")
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, name+" /list", string(body))
+	}
+
+	cancel()
+	require.NoError(t, server.Wait())
+}
+
+func TestStartFunctionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		functions map[string]function.ScwFuncV1
+		options   []local.Option
+	}{
+		{name: "no function"},
+		{name: "invalid name", functions: map[string]function.ScwFuncV1{"My_Function": echoFunction}},
+		{name: "nil handler", functions: map[string]function.ScwFuncV1{"users": nil}},
+		{
+			name:      "duplicated name",
+			functions: map[string]function.ScwFuncV1{"users": echoFunction},
+			options:   []local.Option{local.WithFunction("users", echoFunction)},
+		},
+		{
+			name:      "trigger without function",
+			functions: map[string]function.ScwFuncV1{"users": echoFunction},
+			options:   []local.Option{local.WithCronTrigger("@hourly", nil)},
+		},
+		{
+			name:      "invalid function option",
+			functions: map[string]function.ScwFuncV1{"users": echoFunction},
+			options:   []local.Option{local.WithFunction("orders", echoFunction, local.WithCronTrigger("invalid", nil))},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := local.StartFunctions(context.Background(), tt.functions, tt.options...)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,10 +2,34 @@ package local
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 )
 
+const (
+	// timeoutMessage is the body returned by the infrastructure when the function exceeds its timeout.
+	timeoutMessage = "upstream request timeout"
+
+	// handlerErrorMessage is the body returned by the infrastructure when the function handler fails.
+	handlerErrorMessage = "internal server error"
+
+	// requestTooLargeMessage is the body returned by the infrastructure when the request payload is too big.
+	requestTooLargeMessage = "payload too large"
+
+	// responseTooLargeMessage is the body returned by the infrastructure when the response payload is too big.
+	responseTooLargeMessage = "response payload too large"
+
+	// missingTokenMessage is the body returned by the infrastructure when a private function is called without token.
+	missingTokenMessage = "missing authentication token"
+
+	// invalidTokenMessage is the body returned by the infrastructure when the token of a private function is invalid.
+	invalidTokenMessage = "invalid authentication token"
+)
+
+// authTokenHeader is the header carrying the token required to call private functions.
+const authTokenHeader = "X-Auth-Token"
+
 // InjectIngressHeaders simulates the infrastructure input layer where your FaaS will be deployed.
 func InjectIngressHeaders(httpReq *http.Request) {
 	reqID, err := uuid.NewUUID()
@@ -28,3 +52,14 @@ func InjectIngressHeaders(httpReq *http.Request) {
 func InjectEgressHeaders(httpResp http.ResponseWriter) {
 	httpResp.Header().Set("server", "envoy")
 }
+
+// writeInfraError simulates an error returned by the infrastructure layer instead of your FaaS response.
+func writeInfraError(httpResp http.ResponseWriter, statusCode int, message string) {
+	InjectEgressHeaders(httpResp)
+
+	httpResp.Header().Set("Content-Type", "text/plain")
+	httpResp.Header().Set("Content-Length", strconv.Itoa(len(message)))
+	httpResp.WriteHeader(statusCode)
+
+	_, _ = httpResp.Write([]byte(message))
+}
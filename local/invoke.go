@@ -0,0 +1,208 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+)
+
+// errHandlerPanic is returned when a panic is recovered from the handler.
+var errHandlerPanic = errors.New("handler panicked")
+
+// versionedHandler is a handler normalized by the entry points, at most one of its versions is set. None is set when
+// only mounted functions are served.
+type versionedHandler struct {
+	v1 function.ScwFuncV1
+	v2 function.ScwFuncV2
+}
+
+// newVersionedHandler detects the version of the handler given to an entry point.
+func newVersionedHandler[H function.Handler](handler H) versionedHandler {
+	handlerV1, _ := function.AsV1(handler)
+	handlerV2, _ := function.AsV2(handler)
+
+	return versionedHandler{v1: handlerV1, v2: handlerV2}
+}
+
+// isNil reports whether no handler is set.
+func (h versionedHandler) isNil() bool {
+	return h.v1 == nil && h.v2 == nil
+}
+
+// invoke calls the handler according to its version and returns its response.
+// reqForFaaS is the request received by the sub-runtime, bodyBytes the raw body of the original request.
+//
+//nolint:gocritic
+func (s *Server) invoke(
+	reqForFaaS *http.Request,
+	bodyBytes []byte,
+	handler versionedHandler,
+	event core.APIGatewayProxyRequest,
+) (*core.ResponseHTTP, error) {
+	if handler.v2 != nil {
+		return s.invokeV2(reqForFaaS, bodyBytes, handler.v2, event)
+	}
+
+	handlerV1 := handler.v1
+	if handlerV1 == nil {
+		return nil, function.ErrUnsupportedHandler
+	}
+
+	writerRecorder := httptest.NewRecorder()
+
+	err := s.invokeHandler(s.invocationContext(reqForFaaS), event, func(ctx context.Context) error {
+		handlerV1(writerRecorder, reqForFaaS.WithContext(ctx))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Body is closed but linter reports it.
+	//nolint:bodyclose
+	recorderResp := writerRecorder.Result()
+	defer func() {
+		if recorderResp.Body != nil {
+			recorderResp.Body.Close()
+		}
+	}()
+
+	coreResp, err := core.GetResponse(recorderResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return coreResp, nil
+}
+
+// invokeV2 calls a ScwFuncV2 handler with the event built from the sub-runtime request, so it carries the headers
+// injected by the infrastructure, and a context carrying the execution context and request ID.
+//
+//nolint:gocritic
+func (s *Server) invokeV2(
+	reqForFaaS *http.Request,
+	bodyBytes []byte,
+	handler function.ScwFuncV2,
+	event core.APIGatewayProxyRequest,
+) (*core.ResponseHTTP, error) {
+	handlerEvent := s.eventFormatter.Format(reqForFaaS, bodyBytes)
+
+	var resp core.ResponseHTTP
+
+	err := s.invokeHandler(s.invocationContext(reqForFaaS), event, func(ctx context.Context) error {
+		var errHandler error
+
+		resp, errHandler = handler(ctx, handlerEvent)
+
+		return errHandler
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Headers == nil {
+		resp.Headers = map[string][]string{}
+	}
+
+	if resp.StatusCode == 0 {
+		resp.StatusCode = http.StatusOK
+	}
+
+	return &resp, nil
+}
+
+// invocationContext returns the context given to the handler, carrying the execution context of the server and the
+// request ID injected by the infrastructure. Reads of undeclared environment variables are reported.
+func (s *Server) invocationContext(reqForFaaS *http.Request) context.Context {
+	ctx := core.WithRequestID(reqForFaaS.Context(), reqForFaaS.Header.Get("X-Request-Id"))
+	ctx = s.envContext(ctx)
+
+	return core.WithExecutionContext(ctx, s.executionContext)
+}
+
+// handlerResult is the outcome of a handler run in its own goroutine, panicked is set if it panicked.
+type handlerResult struct {
+	err       error
+	panicked  bool
+	recovered any
+}
+
+// invokeHandler runs call, if a timeout is configured the context given to call carries a deadline and
+// context.DeadlineExceeded is returned when call did not complete on time.
+// A panic of call is raised again in the calling goroutine, so it is handled as if there was no timeout.
+//
+//nolint:gocritic
+func (s *Server) invokeHandler(ctx context.Context, event core.APIGatewayProxyRequest, call func(context.Context) error) error {
+	if s.timeout <= 0 {
+		return s.callHandler(ctx, event, call)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	done := make(chan handlerResult, 1)
+
+	go func() {
+		result := handlerResult{panicked: true}
+
+		defer func() {
+			if result.panicked {
+				result.recovered = recover()
+			}
+
+			done <- result
+		}()
+
+		result.err = s.callHandler(ctx, event, call)
+		result.panicked = false
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicked {
+			panic(result.recovered)
+		}
+
+		return result.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// callHandler runs call, when panic recovery is enabled a panic is logged with the event that triggered it
+// and errHandlerPanic is returned.
+//
+//nolint:gocritic
+func (s *Server) callHandler(ctx context.Context, event core.APIGatewayProxyRequest, call func(context.Context) error) (err error) {
+	if s.panicRecovery {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				s.logPanic(recovered, debug.Stack(), event)
+
+				err = errHandlerPanic
+			}
+		}()
+	}
+
+	return call(ctx)
+}
+
+// logPanic logs the recovered value, its stack trace and the event given to the handler.
+//
+//nolint:gocritic
+func (s *Server) logPanic(recovered any, stack []byte, event core.APIGatewayProxyRequest) {
+	eventJSON, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		eventJSON = []byte(err.Error())
+	}
+
+	s.logger.Error("handler panic recovered", "panic", fmt.Sprint(recovered), "stack", string(stack), "event", string(eventJSON))
+}
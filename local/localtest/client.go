@@ -0,0 +1,189 @@
+// Package localtest provides an in-process client to write unit tests of function handlers.
+//
+// Requests are processed by local.NewHandler, as by the local server, without opening any port:
+//
+//	func TestHandle(t *testing.T) {
+//		client := localtest.NewClient(t, Handle)
+//
+//		resp := client.PostJSON("/orders", map[string]any{"id": 1})
+//		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+//	}
+package localtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Client sends requests to a handler in-process. Every response is checked against invariants of the platform:
+// the Content-Length header matches the body, textual bodies are valid UTF-8 and the infrastructure headers are set.
+type Client struct {
+	t       testing.TB
+	handler http.Handler
+}
+
+// NewClient creates a client invoking handler, options are applied once as for local.ServeHandler. The test fails
+// if the handler or the options are invalid.
+func NewClient[H function.Handler](t testing.TB, handler H, options ...local.Option) *Client {
+	t.Helper()
+
+	processor, err := local.NewHandler(handler, options...)
+	require.NoError(t, err)
+
+	return &Client{t: t, handler: processor}
+}
+
+// Do sends the request to the handler and returns the response.
+func (c *Client) Do(req *http.Request) *Response {
+	c.t.Helper()
+
+	recorder := httptest.NewRecorder()
+
+	c.handler.ServeHTTP(recorder, req)
+
+	resp := &Response{
+		ResponseHTTP: core.ResponseHTTP{
+			StatusCode: recorder.Code,
+			Body:       recorder.Body.Bytes(),
+			Headers:    recorder.Header(),
+		},
+		t: c.t,
+	}
+
+	resp.checkInvariants()
+
+	return resp
+}
+
+// Get sends a GET request to path, headers are given as key, value pairs.
+func (c *Client) Get(path string, headers ...string) *Response {
+	c.t.Helper()
+
+	return c.Do(newRequest(c.t, http.MethodGet, path, nil, headers))
+}
+
+// Post sends a POST request to path with the given body, headers are given as key, value pairs.
+func (c *Client) Post(path string, body []byte, headers ...string) *Response {
+	c.t.Helper()
+
+	return c.Do(newRequest(c.t, http.MethodPost, path, body, headers))
+}
+
+// PostJSON sends a POST request to path with value encoded as JSON body and the matching Content-Type header.
+func (c *Client) PostJSON(path string, value any, headers ...string) *Response {
+	c.t.Helper()
+
+	body, err := json.Marshal(value)
+	require.NoError(c.t, err)
+
+	return c.Do(newRequest(c.t, http.MethodPost, path, body, append([]string{"Content-Type", "application/json"}, headers...)))
+}
+
+// Invoke sends the request described by an event to the handler.
+//
+//nolint:gocritic
+func (c *Client) Invoke(event core.APIGatewayProxyRequest) *Response {
+	c.t.Helper()
+
+	req, err := local.NewEventRequest(&event)
+	require.NoError(c.t, err)
+
+	if req.Host == "" {
+		req.Host = "localhost"
+	}
+
+	return c.Do(req)
+}
+
+// newRequest creates a request, headers are given as key, value pairs.
+func newRequest(t testing.TB, method, path string, body []byte, headers []string) *http.Request {
+	t.Helper()
+
+	require.Zero(t, len(headers)%2, "headers must be given as key, value pairs")
+
+	var bodyReader io.Reader = http.NoBody
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req := httptest.NewRequest(method, path, bodyReader)
+
+	for idx := 0; idx < len(headers); idx += 2 {
+		req.Header.Add(headers[idx], headers[idx+1])
+	}
+
+	return req
+}
+
+// Response is a response of the handler as received by the caller of the function.
+type Response struct {
+	core.ResponseHTTP
+
+	t testing.TB
+}
+
+// Header returns the first value of a response header.
+func (r *Response) Header(key string) string {
+	return http.Header(r.Headers).Get(key)
+}
+
+// Text returns the body as a string.
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// JSON decodes the body into target and fails the test if it is not valid JSON.
+func (r *Response) JSON(target any) {
+	r.t.Helper()
+
+	require.NoError(r.t, json.Unmarshal(r.Body, target), "body is not valid JSON: %s", r.Body)
+}
+
+// AssertPlatformHeaders checks the headers added by the platform to responses of the handler: the request ID,
+// CORS headers and the server header. Errors returned by the infrastructure itself, like timeouts, do not have them.
+func (r *Response) AssertPlatformHeaders() {
+	r.t.Helper()
+
+	assert.NotEmpty(r.t, r.Header("X-Request-Id"), "X-Request-Id header is missing")
+	assert.NotEmpty(r.t, r.Header("Access-Control-Allow-Origin"), "CORS headers are missing")
+	assert.Equal(r.t, "envoy", r.Header("Server"))
+}
+
+// checkInvariants checks properties every response of the platform has.
+func (r *Response) checkInvariants() {
+	r.t.Helper()
+
+	assert.Equal(r.t, "envoy", r.Header("Server"), "responses go through the infrastructure")
+
+	if contentLength := r.Header("Content-Length"); contentLength != "" {
+		assert.Equal(r.t, strconv.Itoa(len(r.Body)), contentLength, "Content-Length does not match the body")
+	}
+
+	if isTextual(r.Header("Content-Type")) {
+		assert.True(r.t, utf8.Valid(r.Body), "textual body is not valid UTF-8, binary data must use a binary Content-Type")
+	}
+}
+
+// isTextual returns true for media types of text documents.
+func isTextual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,145 @@
+package localtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/scaleway/serverless-functions-go/local/localtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientGet(t *testing.T) {
+	t.Parallel()
+
+	client := localtest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "value", r.Header.Get("X-Test"))
+		assert.Equal(t, "1", r.URL.Query().Get("page"))
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("page 1"))
+	})
+
+	resp := client.Get("/items?page=1", "X-Test", "value")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "page 1", resp.Text())
+	resp.AssertPlatformHeaders()
+}
+
+func TestClientPostJSON(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		ID int `json:"id"`
+	}
+
+	client := localtest.NewClient(t, function.JSON(func(ctx context.Context, req order) (order, error) {
+		return order{ID: req.ID * 2}, nil
+	}))
+
+	resp := client.PostJSON("/orders", order{ID: 21})
+
+	var created order
+	resp.JSON(&created)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 42, created.ID)
+	assert.Equal(t, "application/json", resp.Header("Content-Type"))
+	resp.AssertPlatformHeaders()
+}
+
+func TestClientInvoke(t *testing.T) {
+	t.Parallel()
+
+	client := localtest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"method": r.Method, "payload": string(body)})
+	})
+
+	resp := client.Invoke(core.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodDelete,
+		Path:       "/items/1",
+		Body:       "delete me",
+	})
+
+	var decoded map[string]string
+	resp.JSON(&decoded)
+
+	assert.Equal(t, map[string]string{"method": http.MethodDelete, "payload": "delete me"}, decoded)
+}
+
+func TestClientOptions(t *testing.T) {
+	t.Parallel()
+
+	client := localtest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, local.WithTimeout(10*time.Millisecond))
+
+	resp := client.Get("/")
+
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+}
+
+// failureRecorder records the failures of a test instead of failing it.
+type failureRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (f *failureRecorder) Helper() {}
+
+func (f *failureRecorder) Errorf(string, ...any) {
+	f.failed = true
+}
+
+func (f *failureRecorder) FailNow() {
+	f.failed = true
+	runtime.Goexit()
+}
+
+func TestClientInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	recorder := &failureRecorder{TB: t}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		localtest.NewClient(recorder, func(w http.ResponseWriter, r *http.Request) {},
+			local.WithConfigFile("testdata/nonexistent.yml"))
+	}()
+
+	<-done
+
+	assert.True(t, recorder.failed)
+}
+
+func TestClientOptionsAppliedOnce(t *testing.T) {
+	t.Setenv("LOCALTEST_GREETING", "")
+
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte("LOCALTEST_GREETING=hello\n"), 0o600))
+
+	client := localtest.NewClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(os.Getenv("LOCALTEST_GREETING")))
+	}, local.WithEnvFile(envFile))
+
+	require.NoError(t, os.WriteFile(envFile, []byte("LOCALTEST_GREETING=changed\n"), 0o600))
+
+	assert.Equal(t, "hello", client.Get("/").Text())
+	assert.Equal(t, "hello", client.Get("/").Text())
+}
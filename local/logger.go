@@ -0,0 +1,120 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/scaleway/serverless-functions-go/framework/logging"
+)
+
+const (
+	// envLogFormat can be set to "json" or "console" to choose the format of the local server logs.
+	envLogFormat = "SCW_LOG_FORMAT"
+
+	// envCI is set by most CI providers, logs are written as JSON lines when it is set.
+	envCI = "CI"
+
+	// envNoColor disables colors of console logs, see https://no-color.org.
+	envNoColor = "NO_COLOR"
+
+	logFormatJSON    = "json"
+	logFormatConsole = "console"
+)
+
+// newDefaultLogger creates the logger of the local server, level is read from SCW_LOG_LEVEL. Logs are human-friendly
+// for local runs and JSON lines on CI, SCW_LOG_FORMAT can be used to force one format.
+func newDefaultLogger() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: logging.LevelFromEnv()}
+
+	format := os.Getenv(envLogFormat)
+	if format == "" {
+		format = logFormatConsole
+
+		if os.Getenv(envCI) != "" {
+			format = logFormatJSON
+		}
+	}
+
+	if format == logFormatJSON {
+		return slog.New(logging.NewHandler(os.Stderr, opts))
+	}
+
+	return slog.New(logging.NewConsoleHandler(os.Stderr, os.Getenv(envNoColor) == "", opts))
+}
+
+// maskingHandler hides secret values in the message and attributes of records before writing them with inner.
+type maskingHandler struct {
+	inner  slog.Handler
+	masker *strings.Replacer
+}
+
+// newMaskingLogger returns a logger writing to the handler of logger with secret values masked by masker.
+func newMaskingLogger(logger *slog.Logger, masker *strings.Replacer) *slog.Logger {
+	return slog.New(&maskingHandler{inner: logger.Handler(), masker: masker})
+}
+
+// Enabled implements slog.Handler.
+func (h *maskingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+//
+//nolint:gocritic
+func (h *maskingHandler) Handle(ctx context.Context, record slog.Record) error {
+	masked := slog.NewRecord(record.Time, record.Level, h.masker.Replace(record.Message), record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(h.maskAttr(attr))
+
+		return true
+	})
+
+	return h.inner.Handle(ctx, masked)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *maskingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		masked = append(masked, h.maskAttr(attr))
+	}
+
+	return &maskingHandler{inner: h.inner.WithAttrs(masked), masker: h.masker}
+}
+
+// WithGroup implements slog.Handler.
+func (h *maskingHandler) WithGroup(name string) slog.Handler {
+	return &maskingHandler{inner: h.inner.WithGroup(name), masker: h.masker}
+}
+
+// maskAttr masks secret values in attr, values that are not strings are masked in their text representation.
+func (h *maskingHandler) maskAttr(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+
+		masked := make([]any, 0, len(group))
+		for _, groupAttr := range group {
+			masked = append(masked, h.maskAttr(groupAttr))
+		}
+
+		return slog.Group(attr.Key, masked...)
+	case slog.KindString:
+		return slog.String(attr.Key, h.masker.Replace(value.String()))
+	case slog.KindAny:
+		text := fmt.Sprint(value.Any())
+		if maskedText := h.masker.Replace(text); maskedText != text {
+			return slog.String(attr.Key, maskedText)
+		}
+
+		return slog.Attr{Key: attr.Key, Value: value}
+	default:
+		return slog.Attr{Key: attr.Key, Value: value}
+	}
+}
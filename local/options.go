@@ -1,7 +1,17 @@
 package local
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
 )
 
 // Option type used for option pattern to add parameters to local server.
@@ -9,13 +19,152 @@ type Option func(*Server)
 
 // Server represent the local server with it's parameters
 type Server struct {
-	port string
+	port          string
+	timeout       time.Duration
+	strictLimits  bool
+	panicRecovery bool
+	private       bool
+	keyPair       *KeyPair
+	cors          *core.CORSPolicy
+
+	eventFormatter core.EventFormatter
+
+	executionContext core.ExecutionContext
+	logger           *slog.Logger
+	triggers         []trigger
+	recorder         *recorder
+	functions        []*mountedFunction
+
+	// configFiles are applied once all options are applied, see WithConfigFile.
+	configFiles []string
+
+	// env holds the environment variables declared for the function, including secrets which are also in secrets.
+	env     map[string]string
+	secrets map[string]string
+
+	// masker replaces secret values, it is nil when there is no secret.
+	masker *strings.Replacer
+
+	// undeclaredEnv holds the undeclared environment variables read by the handler, to report them once.
+	undeclaredEnv sync.Map
+
+	// err is set by options given invalid parameters, it is returned when the server starts.
+	err error
+
+	listener   net.Listener
+	httpServer *http.Server
+	done       chan error
+	triggersWG sync.WaitGroup
 }
 
-// WithPort can be used to select a port to run the test server, if no port given the server will use an available port given by system and
-// print it to stdout.
+// WithPort can be used to select a port to run the test server, if no port given the server will use an available port given by system.
+// ServeHandler and ServeFunctions print the port to stdout, use Server.Port when the server is started with Start.
 func WithPort(port int) Option {
 	return func(s *Server) {
 		s.port = fmt.Sprintf("%d", port)
 	}
 }
+
+// WithTimeout sets the maximum duration of an invocation, like the timeout configured on your deployed function.
+// The request given to the handler carries a deadline, if the handler does not complete on time the server logs it
+// and returns the same error as the platform (504 Gateway Timeout).
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// WithStrictLimits rejects requests and responses with a payload bigger than 6 MiB as the platform does, instead of
+// only logging a warning. The size is measured on the bytes actually read so chunked bodies are also checked.
+func WithStrictLimits() Option {
+	return func(s *Server) {
+		s.strictLimits = true
+	}
+}
+
+// WithPanicRecovery recovers panics from the handler and returns a 500 error as the platform does, instead of crashing
+// the local server. The stack trace and the event that triggered the panic are logged.
+func WithPanicRecovery() Option {
+	return func(s *Server) {
+		s.panicRecovery = true
+	}
+}
+
+// WithExecutionContext overrides the execution context given to the handler, by default it is read from the
+// environment variables set by Scaleway Functions runtime. Zero values of execCtx keep the default value.
+func WithExecutionContext(execCtx core.ExecutionContext) Option {
+	return func(s *Server) {
+		if execCtx.MemoryLimitInMB > 0 {
+			s.executionContext.MemoryLimitInMB = execCtx.MemoryLimitInMB
+		}
+
+		if execCtx.FunctionName != "" {
+			s.executionContext.FunctionName = execCtx.FunctionName
+		}
+
+		if execCtx.FunctionVersion != "" {
+			s.executionContext.FunctionVersion = execCtx.FunctionVersion
+		}
+
+		if execCtx.NamespaceName != "" {
+			s.executionContext.NamespaceName = execCtx.NamespaceName
+		}
+
+		if execCtx.Region != "" {
+			s.executionContext.Region = execCtx.Region
+		}
+	}
+}
+
+// WithLogger sets the logger used by the local server to report warnings and errors, by default logs are written to
+// stderr with a level read from SCW_LOG_LEVEL, human-friendly locally and as JSON lines on CI.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// WithCronTrigger invokes the handler on a schedule while the server is running, like a CRON trigger of your
+// deployed function. schedule is a standard CRON expression (e.g. "*/5 * * * *" or "@hourly") evaluated in local
+// time and args is encoded as the JSON body of the scheduled invocations. The status of each run is logged.
+// Invocations carry the HeaderTriggerType header, which is specific to the local emulation.
+func WithCronTrigger(schedule string, args any) Option {
+	return func(s *Server) {
+		cronSchedule, err := parseCronSchedule(schedule)
+		if err != nil {
+			s.err = errors.Join(s.err, err)
+
+			return
+		}
+
+		argsJSON, err := json.Marshal(args)
+		if err != nil {
+			s.err = errors.Join(s.err, fmt.Errorf("cron trigger %q: %w", schedule, err))
+
+			return
+		}
+
+		s.triggers = append(s.triggers, &cronTrigger{expr: schedule, schedule: cronSchedule, args: argsJSON})
+	}
+}
+
+// WithCORS applies policy to responses instead of the default policy of the platform, which allows any origin with
+// the Content-Type header. Preflight requests are answered by the local server without invoking the handler. CORS
+// headers set by the handler take precedence over the policy.
+//
+//nolint:gocritic
+func WithCORS(policy core.CORSPolicy) Option {
+	return func(s *Server) {
+		s.cors = &policy
+	}
+}
+
+// WithBinaryContentTypes replaces the media types of request bodies given base64 encoded to the handler, by default
+// core.DefaultBinaryContentTypes. A type ending with "/*" matches all its subtypes, e.g. "image/*".
+func WithBinaryContentTypes(contentTypes ...string) Option {
+	return func(s *Server) {
+		s.eventFormatter.BinaryContentTypes = contentTypes
+	}
+}
@@ -0,0 +1,249 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+const (
+	// defaultQueueMaxRetries is the number of times a failed SQS message is delivered again by default.
+	defaultQueueMaxRetries = 3
+
+	// defaultQueueRetryDelay is the delay before a failed SQS message is delivered again by default.
+	defaultQueueRetryDelay = time.Second
+)
+
+// ErrInvalidQueueTrigger is returned when a queue trigger is not configured properly.
+var ErrInvalidQueueTrigger = errors.New("invalid queue trigger")
+
+// QueueMessage is a message fed to the handler by a queue trigger.
+type QueueMessage struct {
+	// ID of the message, generated if empty.
+	ID         string            `json:"id"`
+	Body       string            `json:"body"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// QueueEvent is a message delivered by a Messaging and Queuing trigger emulated by the local server, from a SQS
+// queue or a NATS subject. Its metadata is read from the local-only trigger headers, see HeaderTriggerType.
+type QueueEvent struct {
+	// TriggerType is either TriggerTypeSQS or TriggerTypeNATS.
+	TriggerType TriggerType `json:"triggerType"`
+	// Source is the name of the SQS queue or the NATS subject the message was read from.
+	Source    string `json:"source"`
+	MessageID string `json:"messageId"`
+	Body      string `json:"body"`
+	// ReceiveCount is the number of times the message was delivered, it is higher than 1 for retries.
+	ReceiveCount int               `json:"receiveCount"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+// QueueTrigger emulates a Scaleway Messaging and Queuing trigger, messages are read from File, a JSON lines file
+// with one QueueMessage per line, or from the Messages channel until it is closed.
+//
+// As on the platform, an invocation fails if the handler returns a status code outside the 2xx range. Failed
+// messages of SQS triggers are delivered again after RetryDelay, up to MaxRetries times, NATS messages are
+// delivered once.
+type QueueTrigger struct {
+	// Type is TriggerTypeSQS (default) or TriggerTypeNATS.
+	Type TriggerType
+	// Name of the SQS queue or NATS subject, given to the handler.
+	Name string
+
+	File     string
+	Messages <-chan QueueMessage
+
+	// MaxRetries defaults to 3, use a negative value to disable retries.
+	MaxRetries int
+	// RetryDelay defaults to 1 second.
+	RetryDelay time.Duration
+}
+
+// WithQueueTrigger feeds the messages of a queue trigger to the handler while the server is running.
+// Each message is sent as a POST request with the message as body and headers describing the message,
+// use ParseQueueEvent to read them from the event. These headers are specific to the local emulation,
+// see HeaderTriggerType.
+//
+//nolint:gocritic
+func WithQueueTrigger(trigger QueueTrigger) Option {
+	return func(s *Server) {
+		if trigger.Type == "" {
+			trigger.Type = TriggerTypeSQS
+		}
+
+		if trigger.Type != TriggerTypeSQS && trigger.Type != TriggerTypeNATS {
+			s.err = errors.Join(s.err, fmt.Errorf("%w: unknown type %q", ErrInvalidQueueTrigger, trigger.Type))
+
+			return
+		}
+
+		if (trigger.File == "") == (trigger.Messages == nil) {
+			s.err = errors.Join(s.err, fmt.Errorf("%w: exactly one of File or Messages must be set", ErrInvalidQueueTrigger))
+
+			return
+		}
+
+		switch {
+		case trigger.Type == TriggerTypeNATS || trigger.MaxRetries < 0:
+			trigger.MaxRetries = 0
+		case trigger.MaxRetries == 0:
+			trigger.MaxRetries = defaultQueueMaxRetries
+		}
+
+		if trigger.RetryDelay <= 0 {
+			trigger.RetryDelay = defaultQueueRetryDelay
+		}
+
+		s.triggers = append(s.triggers, &trigger)
+	}
+}
+
+// run delivers the messages of the trigger until the source is exhausted or ctx is done.
+func (q *QueueTrigger) run(ctx context.Context, s *Server, handler versionedHandler) {
+	if q.Messages != nil {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-q.Messages:
+				if !ok {
+					return
+				}
+
+				q.deliver(ctx, s, handler, msg)
+			}
+		}
+	}
+
+	file, err := os.Open(q.File)
+	if err != nil {
+		s.logger.Error("queue trigger can not read messages", "queue", q.Name, "error", err)
+
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), payloadSizeLimit*2)
+
+	for line := 1; scanner.Scan() && ctx.Err() == nil; line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var msg QueueMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			s.logger.Error("queue trigger skipped invalid message", "queue", q.Name, "line", line, "error", err)
+
+			continue
+		}
+
+		q.deliver(ctx, s, handler, msg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		s.logger.Error("queue trigger can not read messages", "queue", q.Name, "error", err)
+	}
+}
+
+// deliver invokes the handler with the message, failed invocations are retried according to the trigger settings.
+func (q *QueueTrigger) deliver(ctx context.Context, s *Server, handler versionedHandler, msg QueueMessage) {
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	for receiveCount := 1; ; receiveCount++ {
+		req := triggerRequest(ctx, []byte(msg.Body))
+		req.Header.Set(HeaderTriggerType, string(q.Type))
+		req.Header.Set(HeaderTriggerSource, q.Name)
+		req.Header.Set(HeaderMessageID, msg.ID)
+		req.Header.Set(HeaderMessageReceiveCount, strconv.Itoa(receiveCount))
+
+		for key, value := range msg.Attributes {
+			req.Header.Set(HeaderMessageAttributePrefix+key, value)
+		}
+
+		statusCode := s.invokeTrigger(req, handler)
+		if statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices {
+			s.logger.Info("queue trigger message processed", "queue", q.Name, "message_id", msg.ID, "status", statusCode)
+
+			return
+		}
+
+		if receiveCount > q.MaxRetries {
+			s.logger.Error("queue trigger message dropped", "queue", q.Name, "message_id", msg.ID, "status", statusCode,
+				"attempts", receiveCount)
+
+			return
+		}
+
+		s.logger.Warn("queue trigger message failed, it will be retried", "queue", q.Name, "message_id", msg.ID,
+			"status", statusCode, "attempt", receiveCount)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.RetryDelay):
+		}
+	}
+}
+
+// ParseQueueEvent extracts the message delivered by a Messaging and Queuing trigger emulated by the local server
+// from the event. core.ErrNotSupportedTrigger is returned if the invocation does not carry the trigger headers, see
+// HeaderTriggerType.
+//
+//nolint:gocritic
+func ParseQueueEvent(event core.APIGatewayProxyRequest) (QueueEvent, error) {
+	triggerType := GetTriggerType(event)
+	if triggerType != TriggerTypeSQS && triggerType != TriggerTypeNATS {
+		return QueueEvent{}, core.ErrNotSupportedTrigger
+	}
+
+	body := event.Body
+
+	if event.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return QueueEvent{}, core.ErrReadBody
+		}
+
+		body = string(decoded)
+	}
+
+	queueEvent := QueueEvent{
+		TriggerType:  triggerType,
+		Source:       headerValue(event.Headers, HeaderTriggerSource),
+		MessageID:    headerValue(event.Headers, HeaderMessageID),
+		Body:         body,
+		ReceiveCount: 1,
+	}
+
+	if count, err := strconv.Atoi(headerValue(event.Headers, HeaderMessageReceiveCount)); err == nil && count > 0 {
+		queueEvent.ReceiveCount = count
+	}
+
+	for key, value := range event.Headers {
+		if len(key) > len(HeaderMessageAttributePrefix) &&
+			strings.EqualFold(key[:len(HeaderMessageAttributePrefix)], HeaderMessageAttributePrefix) {
+			if queueEvent.Attributes == nil {
+				queueEvent.Attributes = map[string]string{}
+			}
+
+			queueEvent.Attributes[strings.ToLower(key[len(HeaderMessageAttributePrefix):])] = value
+		}
+	}
+
+	return queueEvent, nil
+}
@@ -0,0 +1,181 @@
+package local_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queueRecorder struct {
+	mu     sync.Mutex
+	events []local.QueueEvent
+}
+
+func (q *queueRecorder) handler(t *testing.T, failures int) func(w http.ResponseWriter, r *http.Request) {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		event, err := core.FormatEvent(r)
+		require.NoError(t, err)
+
+		queueEvent, err := local.ParseQueueEvent(event)
+		require.NoError(t, err)
+
+		q.mu.Lock()
+		q.events = append(q.events, queueEvent)
+		q.mu.Unlock()
+
+		if queueEvent.ReceiveCount <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+func (q *queueRecorder) received() []local.QueueEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]local.QueueEvent(nil), q.events...)
+}
+
+func TestQueueTriggerChannelRetries(t *testing.T) {
+	t.Parallel()
+
+	recorder := &queueRecorder{}
+	messages := make(chan local.QueueMessage, 1)
+	messages <- local.QueueMessage{ID: "msg-1", Body: `{"order":1}`, Attributes: map[string]string{"origin": "shop"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.Start(ctx, recorder.handler(t, 2), local.WithQueueTrigger(local.QueueTrigger{
+		Name:       "orders",
+		Messages:   messages,
+		RetryDelay: 10 * time.Millisecond,
+	}), local.WithLogger(discardLogger()))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(recorder.received()) == 3 }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, server.Wait())
+
+	events := recorder.received()
+	require.Len(t, events, 3)
+
+	for idx, event := range events {
+		assert.Equal(t, local.TriggerTypeSQS, event.TriggerType)
+		assert.Equal(t, "orders", event.Source)
+		assert.Equal(t, "msg-1", event.MessageID)
+		assert.Equal(t, `{"order":1}`, event.Body)
+		assert.Equal(t, idx+1, event.ReceiveCount)
+		assert.Equal(t, map[string]string{"origin": "shop"}, event.Attributes)
+	}
+}
+
+func TestQueueTriggerFileNATS(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "messages.jsonl")
+	require.NoError(t, os.WriteFile(file, []byte(`{"body":"first"}
+
+{"id":"second","body":"second"}
+`), 0o600))
+
+	recorder := &queueRecorder{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.Start(ctx, recorder.handler(t, 1), local.WithQueueTrigger(local.QueueTrigger{
+		Type: local.TriggerTypeNATS,
+		Name: "events.created",
+		File: file,
+	}), local.WithLogger(discardLogger()))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(recorder.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, server.Wait())
+
+	// NATS messages are not retried
+	events := recorder.received()
+	require.Len(t, events, 2)
+	assert.Equal(t, "first", events[0].Body)
+	assert.NotEmpty(t, events[0].MessageID)
+	assert.Equal(t, "second", events[1].MessageID)
+	assert.Equal(t, local.TriggerTypeNATS, events[1].TriggerType)
+}
+
+func TestQueueTriggerInvalid(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	for _, trigger := range []local.QueueTrigger{
+		{Name: "no source"},
+		{Name: "both sources", File: "messages.jsonl", Messages: make(chan local.QueueMessage)},
+		{Name: "unknown type", Type: local.TriggerTypeCron, File: "messages.jsonl"},
+	} {
+		_, err := local.Start(context.Background(), handler, local.WithQueueTrigger(trigger))
+		assert.ErrorIs(t, err, local.ErrInvalidQueueTrigger, trigger.Name)
+	}
+}
+
+func TestGetTriggerType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, local.TriggerTypeHTTP, local.GetTriggerType(core.APIGatewayProxyRequest{}))
+	assert.Equal(t, local.TriggerTypeCron, local.GetTriggerType(core.APIGatewayProxyRequest{
+		Headers: map[string]string{"x-scw-trigger-type": "CRON"},
+	}))
+}
+
+func TestParseQueueEvent(t *testing.T) {
+	t.Parallel()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/", strings.NewReader("message body"))
+	require.NoError(t, err)
+
+	req.Header.Set(local.HeaderTriggerType, string(local.TriggerTypeSQS))
+	req.Header.Set(local.HeaderTriggerSource, "orders")
+	req.Header.Set(local.HeaderMessageID, "message-id")
+	req.Header.Set(local.HeaderMessageReceiveCount, "2")
+	req.Header.Set(local.HeaderMessageAttributePrefix+"Origin", "shop")
+
+	event, err := core.FormatEvent(req)
+	require.NoError(t, err)
+
+	queueEvent, err := local.ParseQueueEvent(event)
+	require.NoError(t, err)
+
+	assert.Equal(t, local.QueueEvent{
+		TriggerType:  local.TriggerTypeSQS,
+		Source:       "orders",
+		MessageID:    "message-id",
+		Body:         "message body",
+		ReceiveCount: 2,
+		Attributes:   map[string]string{"origin": "shop"},
+	}, queueEvent)
+}
+
+func TestParseQueueEventNotSupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := local.ParseQueueEvent(core.APIGatewayProxyRequest{})
+	assert.ErrorIs(t, err, core.ErrNotSupportedTrigger)
+
+	_, err = local.ParseQueueEvent(core.APIGatewayProxyRequest{Headers: map[string]string{local.HeaderTriggerType: "cron"}})
+	assert.ErrorIs(t, err, core.ErrNotSupportedTrigger)
+}
@@ -0,0 +1,272 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+	"github.com/scaleway/serverless-functions-go/framework/function"
+)
+
+// replayIgnoredHeaders are response headers that change on every invocation and are not compared by Replay.
+var replayIgnoredHeaders = []string{"Date", "X-Request-Id", "Forwarded", "X-Forwarded-For", "X-Envoy-External-Address"}
+
+// RecordedInvocation is a line of a record file: the event given to the handler and the response sent back.
+type RecordedInvocation struct {
+	Time     time.Time                   `json:"time"`
+	Event    core.APIGatewayProxyRequest `json:"event"`
+	Response core.ResponseHTTP           `json:"response"`
+}
+
+// ReplayResult is the result of the replay of a recorded invocation.
+type ReplayResult struct {
+	// Line of the invocation in the record file.
+	Line     int
+	Event    core.APIGatewayProxyRequest
+	Expected core.ResponseHTTP
+	Actual   core.ResponseHTTP
+	// Diff describes the differences between expected and actual responses, it is empty if they match.
+	Diff string
+}
+
+// recorder appends invocations to a JSON lines file.
+type recorder struct {
+	path string
+	mu   sync.Mutex
+}
+
+// WithRecorder records every invocation, the event and the response, in a JSON lines file. Records can be used to
+// reproduce bugs with Replay. The file is created if needed, records are appended.
+func WithRecorder(path string) Option {
+	return func(s *Server) {
+		s.recorder = &recorder{path: path}
+	}
+}
+
+// record appends the invocation to the record file.
+func (r *recorder) record(event *core.APIGatewayProxyRequest, resp *httptest.ResponseRecorder, masker *strings.Replacer) error {
+	line, err := json.Marshal(RecordedInvocation{
+		Time:     time.Now().UTC(),
+		Event:    *event,
+		Response: responseFromRecorder(resp),
+	})
+	if err != nil {
+		return err
+	}
+
+	if masker != nil {
+		if line, err = maskJSON(line, masker); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+// maskJSON masks secret values in the strings of a JSON document. Strings are masked once decoded, as JSON escaping
+// would hide from the masker secrets containing characters such as '&' or '"'.
+func maskJSON(document []byte, masker *strings.Replacer) ([]byte, error) {
+	var value any
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(maskValue(value, masker))
+}
+
+// maskValue masks secret values in the strings of a decoded JSON value, object keys included.
+func maskValue(value any, masker *strings.Replacer) any {
+	switch typed := value.(type) {
+	case string:
+		return masker.Replace(typed)
+	case []any:
+		for idx := range typed {
+			typed[idx] = maskValue(typed[idx], masker)
+		}
+
+		return typed
+	case map[string]any:
+		masked := make(map[string]any, len(typed))
+		for key, item := range typed {
+			masked[masker.Replace(key)] = maskValue(item, masker)
+		}
+
+		return masked
+	default:
+		return value
+	}
+}
+
+// Replay invokes the handler with each event recorded in file by WithRecorder and compares the responses to the
+// recorded ones, see ReplayResult.Diff. Options are applied to the server processing replayed events.
+func Replay[H function.Handler](file string, handler H, options ...Option) ([]ReplayResult, error) {
+	if err := function.Validate(handler); err != nil {
+		return nil, err
+	}
+
+	content, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	server := newServer(options...)
+	if server.err != nil {
+		return nil, server.err
+	}
+
+	// replayed invocations must not be recorded again
+	server.recorder = nil
+
+	results := []ReplayResult{}
+
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), payloadSizeLimit*4)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var invocation RecordedInvocation
+		if err := json.Unmarshal(scanner.Bytes(), &invocation); err != nil {
+			return results, fmt.Errorf("%s:%d: %w", file, line, err)
+		}
+
+		req, err := NewEventRequest(&invocation.Event)
+		if err != nil {
+			return results, fmt.Errorf("%s:%d: %w", file, line, err)
+		}
+
+		capture := httptest.NewRecorder()
+		server.process(capture, withLocalHost(req), newVersionedHandler(handler))
+
+		actual := responseFromRecorder(capture)
+
+		results = append(results, ReplayResult{
+			Line:     line,
+			Event:    invocation.Event,
+			Expected: invocation.Response,
+			Actual:   actual,
+			Diff:     diffResponses(&invocation.Response, &actual),
+		})
+	}
+
+	return results, scanner.Err()
+}
+
+// responseFromRecorder converts the response written to a recorder. The body is stored as a JSON string, base64
+// encoded if it is not valid UTF-8.
+func responseFromRecorder(resp *httptest.ResponseRecorder) core.ResponseHTTP {
+	bodyBytes := resp.Body.Bytes()
+	isBase64Encoded := !utf8.Valid(bodyBytes)
+
+	bodyString := string(bodyBytes)
+	if isBase64Encoded {
+		bodyString = base64.StdEncoding.EncodeToString(bodyBytes)
+	}
+
+	// marshalling a string never fails
+	body, _ := json.Marshal(bodyString)
+
+	return core.ResponseHTTP{
+		StatusCode:      resp.Code,
+		Body:            body,
+		Headers:         resp.Header().Clone(),
+		IsBase64Encoded: isBase64Encoded,
+	}
+}
+
+// copyResponse writes the response captured by a recorder to httpResp.
+func copyResponse(httpResp http.ResponseWriter, capture *httptest.ResponseRecorder) {
+	for key, values := range capture.Header() {
+		httpResp.Header()[key] = values
+	}
+
+	httpResp.WriteHeader(capture.Code)
+
+	_, _ = httpResp.Write(capture.Body.Bytes())
+}
+
+// diffResponses describes the differences between two responses, headers that change on every invocation are
+// ignored. It returns an empty string if responses match.
+func diffResponses(expected, actual *core.ResponseHTTP) string {
+	diffs := []string{}
+
+	if expected.StatusCode != actual.StatusCode {
+		diffs = append(diffs, fmt.Sprintf("status code: expected %d, got %d", expected.StatusCode, actual.StatusCode))
+	}
+
+	expectedHeaders := comparableHeaders(expected.Headers)
+	actualHeaders := comparableHeaders(actual.Headers)
+
+	keys := make([]string, 0, len(expectedHeaders)+len(actualHeaders))
+	for key := range expectedHeaders {
+		keys = append(keys, key)
+	}
+
+	for key := range actualHeaders {
+		if _, ok := expectedHeaders[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !reflect.DeepEqual(expectedHeaders[key], actualHeaders[key]) {
+			diffs = append(diffs, fmt.Sprintf("header %s: expected %q, got %q", key, expectedHeaders[key], actualHeaders[key]))
+		}
+	}
+
+	if !bytes.Equal(expected.Body, actual.Body) || expected.IsBase64Encoded != actual.IsBase64Encoded {
+		diffs = append(diffs, fmt.Sprintf("body:\n- %s\n+ %s", expected.Body, actual.Body))
+	}
+
+	return strings.Join(diffs, "\n")
+}
+
+// comparableHeaders returns headers with canonical keys, without the ones ignored by Replay.
+func comparableHeaders(headers map[string][]string) map[string][]string {
+	filtered := make(map[string][]string, len(headers))
+
+	for key, values := range headers {
+		filtered[http.CanonicalHeaderKey(key)] = values
+	}
+
+	for _, key := range replayIgnoredHeaders {
+		delete(filtered, key)
+	}
+
+	return filtered
+}
@@ -0,0 +1,127 @@
+package local_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	recordFile := filepath.Join(t.TempDir(), "record.jsonl")
+
+	greeting := "hello"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		w.Header().Set("X-Greeting", greeting)
+		fmt.Fprintf(w, "%s %s from %s", greeting, body, r.URL.Query().Get("from"))
+	}
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPost, "/greet?from=paris", strings.NewReader("world")),
+		httptest.NewRequest(http.MethodGet, "/greet", http.NoBody),
+	}
+
+	for _, req := range requests {
+		recorder := httptest.NewRecorder()
+		local.CoreProcessing(recorder, req, handler, local.WithRecorder(recordFile))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "hello")
+	}
+
+	assert.Equal(t, 2, countLines(t, recordFile))
+
+	results, err := local.Replay(recordFile, handler)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for _, result := range results {
+		assert.Empty(t, result.Diff)
+	}
+
+	assert.Equal(t, "/greet", results[0].Event.Path)
+	assert.Equal(t, `"hello world from paris"`, string(results[0].Actual.Body))
+
+	greeting = "bye"
+
+	results, err = local.Replay(recordFile, handler)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Contains(t, results[0].Diff, `header X-Greeting: expected ["hello"], got ["bye"]`)
+	assert.Contains(t, results[0].Diff, "- \"hello world from paris\"\n+ \"bye world from paris\"")
+
+	// replay must not append to the record file
+	assert.Equal(t, 2, countLines(t, recordFile))
+}
+
+func TestRecordBinaryResponse(t *testing.T) {
+	t.Parallel()
+
+	recordFile := filepath.Join(t.TempDir(), "record.jsonl")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte{0xff, 0xfe, 0x00})
+	}
+
+	recorder := httptest.NewRecorder()
+	local.CoreProcessing(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody), handler,
+		local.WithRecorder(recordFile))
+
+	results, err := local.Replay(recordFile, handler)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Empty(t, results[0].Diff)
+	assert.True(t, results[0].Actual.IsBase64Encoded)
+	assert.Equal(t, `"//4A"`, string(results[0].Actual.Body))
+}
+
+func TestReplayErrors(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	_, err := local.Replay(filepath.Join(t.TempDir(), "missing.jsonl"), handler)
+	assert.Error(t, err)
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.jsonl")
+	require.NoError(t, os.WriteFile(invalidFile, []byte("not json\n"), 0o600))
+
+	_, err = local.Replay(invalidFile, handler)
+	assert.ErrorContains(t, err, "invalid.jsonl:1")
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	lines := 0
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return lines
+}
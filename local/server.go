@@ -1,52 +1,180 @@
 package local
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"time"
 
+	"github.com/scaleway/serverless-functions-go/framework/core"
 	"github.com/scaleway/serverless-functions-go/framework/function"
 )
 
+const (
+	// shutdownTimeout is the time given to in-flight invocations to complete once the server is asked to stop.
+	shutdownTimeout = 30 * time.Second
+
+	// defaultWriteTimeout is the write timeout of the server when no function timeout is configured.
+	defaultWriteTimeout = 5 * time.Second
+
+	// writeTimeoutMargin is added to the function timeout so the server can still write the timeout response.
+	writeTimeoutMargin = 5 * time.Second
+)
+
+// newServer creates a server with default parameters and applies the given options.
+func newServer(options ...Option) *Server {
+	server := &Server{
+		port:             "0",
+		executionContext: core.GetExecutionContext(),
+		logger:           newDefaultLogger(),
+		eventFormatter:   core.EventFormatter{BinaryContentTypes: core.DefaultBinaryContentTypes},
+	}
+
+	for idx := range options {
+		options[idx](server)
+	}
+
+	server.applyConfigFiles()
+	server.mountFunctions()
+	server.applyEnv()
+	server.maskSecrets()
+
+	return server
+}
+
 // ServeHandler is the entry point for offline testing. It will serve the handler to a local webserver.
+// The handler can be any versioned function prototype, the version is detected to adapt the invocation.
 // Read options.go to check advanced paramenter and documentation.
 //
 // Note that if handler function panics in real life it would make your function return error 500 but
-// in order to keep error trace panic will occurs anywhen while using this testing server.
-func ServeHandler(handler function.ScwFuncV1, options ...Option) {
-	server := Server{
-		port: "0",
+// in order to keep error trace panic will occurs anywhen while using this testing server, unless
+// WithPanicRecovery option is used.
+func ServeHandler[H function.Handler](handler H, options ...Option) {
+	server, err := Start(context.Background(), handler, options...)
+	if err != nil {
+		panic(err)
 	}
 
-	for idx := range options {
-		options[idx](&server)
+	// the port is printed whatever the log level, it is needed to call the function when chosen by the system
+	fmt.Println("Using port:", server.Port())
+
+	server.logger.Info("serving function", "port", server.Port(), "url", server.URL())
+
+	if err := server.Wait(); err != nil {
+		panic(err)
+	}
+}
+
+// Start serves the handler to a local webserver in the background and returns once the server is listening.
+// Cancelling ctx stops the server: new connections are refused and in-flight invocations are given some time
+// to complete before the server is closed. Use Wait to block until the server is fully stopped.
+func Start[H function.Handler](ctx context.Context, handler H, options ...Option) (*Server, error) {
+	if err := function.Validate(handler); err != nil {
+		return nil, err
+	}
+
+	return start(ctx, newVersionedHandler(handler), options...)
+}
+
+// start serves the handler and the mounted functions, handler is nil when only mounted functions are served.
+func start(ctx context.Context, handler versionedHandler, options ...Option) (*Server, error) {
+	server := newServer(options...)
+	if server.err != nil {
+		return nil, server.err
+	}
+
+	if handler.isNil() && len(server.triggers) > 0 {
+		return nil, fmt.Errorf("%w: triggers must be set on a function with WithFunction options", ErrInvalidFunction)
 	}
 
 	listener, err := net.Listen("tcp", ":"+server.port)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	fmt.Println("Using port:", listener.Addr().(*net.TCPAddr).Port)
-
 	decoratedHandler := func(httpResp http.ResponseWriter, httpReq *http.Request) {
-		CoreProcessing(httpResp, httpReq, handler)
+		server.process(httpResp, httpReq, handler)
 
 		if httpReq.Body != nil && httpReq.Body != http.NoBody {
 			httpReq.Body.Close()
 		}
 	}
 
-	srv := &http.Server{
+	writeTimeout := defaultWriteTimeout
+	if server.timeout > 0 {
+		writeTimeout = server.timeout + writeTimeoutMargin
+	}
+
+	server.listener = listener
+	server.done = make(chan error, 1)
+	server.httpServer = &http.Server{
 		ReadTimeout:       5 * time.Second,
-		WriteTimeout:      5 * time.Second,
+		WriteTimeout:      writeTimeout,
 		IdleTimeout:       30 * time.Second,
 		ReadHeaderTimeout: 7 * time.Second,
 		Handler:           http.HandlerFunc(decoratedHandler),
 	}
 
-	if err := srv.Serve(listener); err != nil {
-		panic(err)
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- server.httpServer.Serve(listener)
+	}()
+
+	triggersCtx, stopTriggers := context.WithCancel(ctx)
+	server.startTriggers(triggersCtx, handler)
+	server.startFunctionTriggers(triggersCtx)
+
+	go func() {
+		var err error
+
+		select {
+		case err = <-serveErr:
+		case <-ctx.Done():
+			err = server.shutdown()
+		}
+
+		stopTriggers()
+		server.triggersWG.Wait()
+		server.waitFunctionTriggers()
+
+		server.done <- err
+	}()
+
+	return server, nil
+}
+
+// Addr returns the address the server is listening on, e.g. "[::]:8080".
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Port returns the TCP port the server is listening on, useful when the port was chosen by the system.
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// URL returns the base URL that can be used to call the function served locally.
+func (s *Server) URL() string {
+	return fmt.Sprintf("http://localhost:%d", s.Port())
+}
+
+// Wait blocks until the server is stopped and returns the error that stopped it, if any.
+// A server stopped by cancelling its context returns nil once in-flight invocations are drained.
+func (s *Server) Wait() error {
+	return <-s.done
+}
+
+// shutdown gracefully stops the http server, waiting for in-flight invocations to complete.
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+
+	return nil
 }
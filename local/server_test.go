@@ -1,6 +1,7 @@
 package local_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/scaleway/serverless-functions-go/local"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestServSimpleResponse(t *testing.T) {
@@ -104,6 +106,106 @@ func TestServDumpResponse(t *testing.T) {
 	assert.Contains(t, respStr, "X-Request-Id")
 }
 
+func TestStartGracefulShutdown(t *testing.T) {
+	t.Parallel()
+
+	const testingMessage = "drained"
+
+	started := make(chan struct{})
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(500 * time.Millisecond)
+		_, _ = w.Write([]byte(testingMessage))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.Start(ctx, handler)
+	require.NoError(t, err)
+	assert.NotEmpty(t, server.Addr())
+
+	type result struct {
+		body string
+		err  error
+	}
+
+	results := make(chan result, 1)
+
+	go func() {
+		//nolint:noctx
+		resp, errGet := http.Get(server.URL())
+		if errGet != nil {
+			results <- result{err: errGet}
+
+			return
+		}
+		defer resp.Body.Close()
+
+		body, errRead := io.ReadAll(resp.Body)
+		results <- result{body: string(body), err: errRead}
+	}()
+
+	<-started
+	cancel()
+
+	require.NoError(t, server.Wait())
+
+	res := <-results
+	require.NoError(t, res.err)
+	assert.Equal(t, testingMessage, res.body)
+
+	//nolint:noctx,bodyclose
+	_, err = http.Get(server.URL())
+	assert.Error(t, err)
+}
+
+func TestStartPortInUse(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.Start(ctx, func(w http.ResponseWriter, r *http.Request) {})
+	require.NoError(t, err)
+
+	_, err = local.Start(ctx, func(w http.ResponseWriter, r *http.Request) {}, local.WithPort(server.Port()))
+	assert.Error(t, err)
+}
+
+func TestStartPanicWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/panic" {
+			panic("bad request")
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, err := local.Start(ctx, handler, local.WithTimeout(time.Minute), local.WithLogger(discardLogger()))
+	require.NoError(t, err)
+
+	// the panic aborts the connection as without timeout instead of crashing the process
+	//nolint:noctx,bodyclose
+	_, err = http.Get(server.URL() + "/panic")
+	assert.Error(t, err)
+
+	//nolint:noctx
+	resp, err := http.Get(server.URL())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+}
+
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 //nolint:gosec
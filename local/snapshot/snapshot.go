@@ -0,0 +1,155 @@
+// Package snapshot provides golden file assertions on handler responses, to catch regressions automatically.
+//
+// A snapshot stores the status code, the headers and the body of a response in testdata/snapshots. Headers that
+// change on every invocation, like X-Request-Id, are ignored. Run the tests with SNAPSHOT_UPDATE=1 to create or
+// regenerate snapshots:
+//
+//	SNAPSHOT_UPDATE=1 go test ./... -run TestHandle
+//
+// The -update flag also regenerates snapshots once the test package defines it, with RegisterUpdateFlag or with
+// its own declaration when it already has one for other golden files:
+//
+//	var _ = snapshot.RegisterUpdateFlag()
+//
+//	var _ = flag.Bool("update", false, "update snapshot files")
+//
+// Without one of these declarations, go test rejects the -update flag.
+package snapshot
+
+import (
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/scaleway/serverless-functions-go/framework/function"
+	"github.com/scaleway/serverless-functions-go/local"
+	"github.com/scaleway/serverless-functions-go/local/localtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// envUpdate is set to regenerate snapshots.
+	envUpdate = "SNAPSHOT_UPDATE"
+
+	// updateFlag is the name of the flag regenerating snapshots, see RegisterUpdateFlag.
+	updateFlag = "update"
+)
+
+// Dir is the directory where snapshots are stored, relative to the package under test.
+var Dir = filepath.Join("testdata", "snapshots")
+
+// IgnoredHeaders are the response headers not stored in snapshots because they change on every invocation.
+var IgnoredHeaders = []string{"Date", "X-Request-Id"}
+
+// MatchHandler sends the request to the handler with a localtest.Client and matches the response with the
+// snapshot of the test, see Match.
+func MatchHandler[H function.Handler](t testing.TB, handler H, req *http.Request, options ...local.Option) {
+	t.Helper()
+
+	Match(t, localtest.NewClient(t, handler, options...).Do(req))
+}
+
+// Match compares the response with the snapshot named after the test, it fails the test with a diff if they differ.
+// A missing snapshot fails the test unless snapshots are updated.
+func Match(t testing.TB, resp *localtest.Response) {
+	t.Helper()
+
+	path := filepath.Join(Dir, fileName(t.Name()))
+	actual := Format(resp)
+
+	if updating() {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(actual), 0o600))
+
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		require.Failf(t, "snapshot not found", "%s does not exist, run the test with %s=1 to create it", path, envUpdate)
+	}
+
+	require.NoError(t, err)
+
+	assert.Equal(t, string(expected), actual, "response does not match %s, run the test with %s=1 if it is expected", path, envUpdate)
+}
+
+// RegisterUpdateFlag defines the -update flag regenerating snapshots on the command line of the test binary, unless
+// it is already defined. It reports whether the flag was defined and is meant to be called from a package level
+// variable declaration or from TestMain, before flags are parsed.
+func RegisterUpdateFlag() bool {
+	if flag.Lookup(updateFlag) != nil {
+		return false
+	}
+
+	flag.Bool(updateFlag, false, "update snapshot files")
+
+	return true
+}
+
+// updating reports whether snapshots are regenerated, by SNAPSHOT_UPDATE or the -update flag of the test package.
+func updating() bool {
+	if os.Getenv(envUpdate) != "" {
+		return true
+	}
+
+	if updateValue := flag.Lookup(updateFlag); updateValue != nil {
+		enabled, err := strconv.ParseBool(updateValue.Value.String())
+
+		return err == nil && enabled
+	}
+
+	return false
+}
+
+// Format returns the snapshot of a response: status code, sorted headers without IgnoredHeaders, an empty line and
+// the body. Bodies that are not valid UTF-8 are base64 encoded.
+func Format(resp *localtest.Response) string {
+	builder := strings.Builder{}
+
+	fmt.Fprintf(&builder, "%d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+
+	headers := http.Header(resp.Headers).Clone()
+	for _, key := range IgnoredHeaders {
+		headers.Del(key)
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range headers[key] {
+			fmt.Fprintf(&builder, "%s: %s\n", http.CanonicalHeaderKey(key), value)
+		}
+	}
+
+	builder.WriteString("\n")
+
+	if utf8.Valid(resp.Body) {
+		builder.Write(resp.Body)
+	} else {
+		builder.WriteString("base64:" + base64.StdEncoding.EncodeToString(resp.Body))
+	}
+
+	return builder.String()
+}
+
+// fileName converts a test name into the name of its snapshot file, subtests are stored in subdirectories.
+func fileName(testName string) string {
+	replacer := strings.NewReplacer(" ", "_", ":", "_", "\\", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+	return replacer.Replace(testName) + ".golden"
+}
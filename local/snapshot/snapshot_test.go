@@ -0,0 +1,76 @@
+package snapshot_test
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scaleway/serverless-functions-go/local/localtest"
+	"github.com/scaleway/serverless-functions-go/local/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update is defined by the test package as in most golden file tests, RegisterUpdateFlag must not define it again.
+var _ = flag.Bool("update", false, "update snapshot files")
+
+func handler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Custom", "custom")
+	_, _ = w.Write([]byte(`{"message":"hello"}`))
+}
+
+func TestMatchHandler(t *testing.T) {
+	t.Parallel()
+
+	snapshot.MatchHandler(t, handler, httptest.NewRequest(http.MethodGet, "/hello", http.NoBody))
+}
+
+func TestMatchSubtests(t *testing.T) {
+	t.Parallel()
+
+	client := localtest.NewClient(t, handler)
+
+	t.Run("post request", func(t *testing.T) {
+		t.Parallel()
+
+		snapshot.Match(t, client.Post("/hello", []byte("body")))
+	})
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	client := localtest.NewClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte{0xff, 0x00})
+	})
+
+	formatted := snapshot.Format(client.Get("/"))
+
+	assert.True(t, strings.HasPrefix(formatted, "200 OK\n"))
+	assert.Contains(t, formatted, "\nContent-Type: application/octet-stream\n")
+	assert.NotContains(t, formatted, "X-Request-Id")
+	assert.True(t, strings.HasSuffix(formatted, "\n\nbase64:/wA="))
+}
+
+func TestSnapshotFiles(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "snapshots", "TestMatchHandler.golden"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "X-Custom: custom\n")
+	assert.True(t, strings.HasSuffix(string(content), `{"message":"hello"}`))
+}
+
+func TestRegisterUpdateFlag(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, snapshot.RegisterUpdateFlag())
+	assert.NotNil(t, flag.Lookup("update"))
+}
@@ -1,18 +1,22 @@
 package local
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"io"
 	"net/http"
-	"strings"
 )
 
 type subRuntimeRequest struct {
 	Event struct {
-		HTTPMethod            string            `json:"httpMethod"`
-		Headers               map[string]string `json:"headers"`
-		QueryStringParameters map[string]string `json:"queryStringParameters"`
-		Body                  string            `json:"body"`
+		HTTPMethod                      string              `json:"httpMethod"`
+		Headers                         map[string]string   `json:"headers"`
+		MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+		QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+		MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+		Body                            string              `json:"body"`
+		IsBase64Encoded                 bool                `json:"isBase64Encoded"`
 	} `json:"event"`
 }
 
@@ -37,19 +41,45 @@ func SubProcessing(httpResp http.ResponseWriter, httpReq *http.Request) error {
 
 	httpReq.Method = req.Event.HTTPMethod
 
+	// Multi-value headers and query parameters carry all the values, they take precedence over flattened ones.
 	httpReq.Header = make(map[string][]string, len(req.Event.Headers))
 	for key, value := range req.Event.Headers {
 		httpReq.Header[key] = []string{value}
 	}
 
+	for key, values := range req.Event.MultiValueHeaders {
+		if len(values) > 0 {
+			httpReq.Header[key] = values
+		}
+	}
+
 	params := httpReq.URL.Query()
 	for key, value := range req.Event.QueryStringParameters {
 		params.Set(key, value)
 	}
 
+	for key, values := range req.Event.MultiValueQueryStringParameters {
+		if len(values) > 0 {
+			params[key] = values
+		}
+	}
+
 	httpReq.URL.RawQuery = params.Encode()
 
-	httpReq.Body = io.NopCloser(strings.NewReader(req.Event.Body))
+	body := []byte(req.Event.Body)
+
+	// Binary bodies are base64 encoded in the event, the handler receives them decoded.
+	if req.Event.IsBase64Encoded {
+		body, err = base64.StdEncoding.DecodeString(req.Event.Body)
+		if err != nil {
+			httpResp.WriteHeader(http.StatusInternalServerError)
+			_, _ = httpResp.Write([]byte("Cannot decode base64 body of event from core runtime"))
+
+			return err
+		}
+	}
+
+	httpReq.Body = io.NopCloser(bytes.NewReader(body))
 
 	return nil
 }
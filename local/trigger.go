@@ -0,0 +1,167 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/scaleway/serverless-functions-go/framework/core"
+)
+
+// TriggerType identifies the source of an invocation made by the local server.
+type TriggerType string
+
+// Trigger types emulated by the local server.
+const (
+	TriggerTypeHTTP TriggerType = "http"
+	TriggerTypeCron TriggerType = "cron"
+	TriggerTypeSQS  TriggerType = "sqs"
+	TriggerTypeNATS TriggerType = "nats"
+)
+
+// Headers set on invocations made by the triggers emulated by the local server, HTTP invocations do not have them.
+// They are a convention of the local emulation: deployed functions do not receive them.
+const (
+	HeaderTriggerType            = "X-Scw-Trigger-Type"
+	HeaderTriggerSource          = "X-Scw-Trigger-Source"
+	HeaderMessageID              = "X-Scw-Message-Id"
+	HeaderMessageReceiveCount    = "X-Scw-Message-Receive-Count"
+	HeaderMessageAttributePrefix = "X-Scw-Message-Attribute-"
+)
+
+// trigger is an event source invoking the handler in the background while the server is running.
+type trigger interface {
+	run(ctx context.Context, s *Server, handler versionedHandler)
+}
+
+// startTriggers runs the triggers of the server until ctx is done.
+func (s *Server) startTriggers(ctx context.Context, handler versionedHandler) {
+	for idx := range s.triggers {
+		s.triggersWG.Add(1)
+
+		go func(trig trigger) {
+			defer s.triggersWG.Done()
+
+			trig.run(ctx, s, handler)
+		}(s.triggers[idx])
+	}
+}
+
+// cronTrigger invokes the handler on a schedule, as a CRON trigger of the platform does.
+type cronTrigger struct {
+	expr     string
+	schedule *cronSchedule
+	args     json.RawMessage
+}
+
+// run invokes the handler at each activation of the schedule until ctx is done.
+func (c *cronTrigger) run(ctx context.Context, s *Server, handler versionedHandler) {
+	for {
+		next := c.schedule.next(time.Now())
+		if next.IsZero() {
+			s.logger.Warn("cron trigger will never run", "schedule", c.expr)
+
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+			c.invoke(ctx, s, handler)
+		}
+	}
+}
+
+// invoke calls the handler like a scheduled invocation of the platform: a POST request with the JSON arguments
+// of the trigger as body. The trigger type header is only set by the local emulation.
+func (c *cronTrigger) invoke(ctx context.Context, s *Server, handler versionedHandler) {
+	req := triggerRequest(ctx, c.args)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTriggerType, string(TriggerTypeCron))
+
+	statusCode := s.invokeTrigger(req, handler)
+	if statusCode >= http.StatusBadRequest {
+		s.logger.Error("cron trigger invocation failed", "schedule", c.expr, "status", statusCode)
+
+		return
+	}
+
+	s.logger.Info("cron trigger invoked", "schedule", c.expr, "status", statusCode)
+}
+
+// triggerRequestKey is the context key marking requests sent by a trigger.
+type triggerRequestKey struct{}
+
+// triggerRequest creates the request sent to the function by a trigger.
+func triggerRequest(ctx context.Context, body []byte) *http.Request {
+	ctx = context.WithValue(ctx, triggerRequestKey{}, true)
+
+	return httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)).WithContext(ctx)
+}
+
+// isTriggerRequest reports whether the request was sent by a trigger of the server.
+func isTriggerRequest(httpReq *http.Request) bool {
+	fromTrigger, _ := httpReq.Context().Value(triggerRequestKey{}).(bool)
+
+	return fromTrigger
+}
+
+// invokeTrigger processes a request sent by a trigger and returns the resulting status code.
+func (s *Server) invokeTrigger(req *http.Request, handler versionedHandler) int {
+	recorder := httptest.NewRecorder()
+
+	s.process(recorder, req, handler)
+
+	return recorder.Code
+}
+
+// hasTriggers reports whether triggers are set on the server or on one of its mounted functions.
+func (s *Server) hasTriggers() bool {
+	if len(s.triggers) > 0 {
+		return true
+	}
+
+	for _, mounted := range s.functions {
+		if len(mounted.server.triggers) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetTriggerType returns the type of trigger emulated by the local server that made the invocation, read from the
+// HeaderTriggerType header. Invocations without it, direct HTTP calls, are reported as TriggerTypeHTTP.
+//
+//nolint:gocritic
+func GetTriggerType(event core.APIGatewayProxyRequest) TriggerType {
+	if triggerType := headerValue(event.Headers, HeaderTriggerType); triggerType != "" {
+		return TriggerType(strings.ToLower(triggerType))
+	}
+
+	return TriggerTypeHTTP
+}
+
+// headerValue returns the value of a header of the event, header names are case insensitive.
+func headerValue(headers map[string]string, key string) string {
+	if value, ok := headers[key]; ok {
+		return value
+	}
+
+	for headerKey, value := range headers {
+		if strings.EqualFold(headerKey, key) {
+			return value
+		}
+	}
+
+	return ""
+}